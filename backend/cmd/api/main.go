@@ -9,6 +9,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/cache"
 	"github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/config"
 	"github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/db"
 	apphttp "github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/http"
@@ -39,8 +40,14 @@ func main() {
 
 	userRepo := repository.NewUserRepository(dbConn)
 	translationRepo := repository.NewTranslationRepository(dbConn)
+	orderRepo := repository.NewOrderRepository(dbConn)
 	fileRepo := repository.NewFileRepository(dbConn)
+	memoryRepo := repository.NewMemoryRepository(dbConn)
+	segmentEditRepo := repository.NewSegmentEditRepository(dbConn)
+	modelRepo := repository.NewModelRepository(dbConn)
+	priceListRepo := repository.NewPriceListRepository(dbConn)
 	paymentRepo := repository.NewPaymentRepository(dbConn)
+	glossaryRepo := repository.NewGlossaryRepository(dbConn)
 
 	queueClient, err := queue.NewClient(cfg.RedisURL)
 	if err != nil {
@@ -48,21 +55,100 @@ func main() {
 	}
 	defer queueClient.Close()
 
+	redisCache, err := cache.NewRedisCache(cfg.RedisURL)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to connect redis cache")
+	}
+	defer redisCache.Close()
+
 	storageProvider, err := initStorage(cfg)
 	if err != nil {
 		log.Fatal().Err(err).Msg("failed to init storage")
 	}
 
-	deepLClient := translation.NewDeepLClient(cfg.DeepLApiKey, translation.DeepLOptions{UseFreeAPI: cfg.AppEnv != "production"})
-	otranslatorClient := translation.NewOTranslatorClient(cfg.OTranslatorKey, translation.OTranslatorOptions{BaseURL: cfg.OTranslatorBase, Timeout: 2 * time.Minute})
+	retryPolicy := translation.RetryPolicy{
+		MaxAttempts: cfg.ProviderMaxAttempts,
+		BaseDelay:   cfg.ProviderRetryBaseDelay,
+		MaxDelay:    cfg.ProviderRetryMaxDelay,
+	}
+	deepLClient := translation.NewDeepLClient(cfg.DeepLApiKey, translation.DeepLOptions{UseFreeAPI: cfg.AppEnv != "production", Retry: retryPolicy})
+	otranslatorClient := translation.NewOTranslatorClient(cfg.OTranslatorKey, translation.OTranslatorOptions{BaseURL: cfg.OTranslatorBase, Timeout: 2 * time.Minute, Retry: retryPolicy})
+	providers := translation.NewRegistry(
+		translation.NewDeepLProvider(deepLClient),
+		translation.NewOTranslatorProvider(otranslatorClient),
+	)
+	if cfg.LibreTranslateEnabled {
+		libreClient := translation.NewLibreTranslateClient(cfg.LibreTranslateKey, translation.LibreTranslateOptions{BaseURL: cfg.LibreTranslateURL, Timeout: cfg.LibreTranslateTimeout, Retry: retryPolicy})
+		providers.Register(translation.NewLibreTranslateProvider(libreClient, cfg.LibreTranslateChunkChars))
+		translation.Catalog = append(translation.Catalog, translation.LibreTranslateModel)
+	}
+	if cfg.LLMEnabled {
+		llmClient := translation.NewLLMClient(cfg.LLMApiKey, translation.LLMOptions{BaseURL: cfg.LLMBaseURL, Model: cfg.LLMModel, Timeout: cfg.LLMTimeout, Retry: retryPolicy})
+		providers.Register(translation.NewLLMProvider(llmClient, translation.LLMProviderOptions{
+			ChunkChars:      cfg.LLMChunkChars,
+			ContextSegments: cfg.LLMContextSegments,
+		}))
+		translation.Catalog = append(translation.Catalog, translation.LLMModels...)
+	}
+	if cfg.MockProviderEnabled || cfg.MockProviderOverride {
+		mockProvider := translation.NewMockProvider(translation.MockOptions{
+			Latency:     cfg.MockProviderLatency,
+			FailureRate: cfg.MockProviderFailureRate,
+			Seed:        cfg.MockProviderSeed,
+		})
+		providers.Register(mockProvider)
+		translation.Catalog = append(translation.Catalog, translation.MockModel)
+		if cfg.MockProviderOverride {
+			providers.RegisterAs(translation.ProviderDeepL, mockProvider)
+			providers.RegisterAs(translation.ProviderOTranslator, mockProvider)
+			if cfg.LibreTranslateEnabled {
+				providers.RegisterAs(translation.ProviderLibreTranslate, mockProvider)
+			}
+			if cfg.LLMEnabled {
+				providers.RegisterAs(translation.ProviderLLM, mockProvider)
+			}
+			log.Warn().Msg("all translation models routed to mock provider")
+		}
+	}
+
+	failoverPolicy, err := translation.ParseFailoverPolicy(cfg.FailoverEnabled, cfg.FailoverOn)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid failover configuration")
+	}
+
+	quotaMonitor := services.NewQuotaMonitor(cfg.QuotaPollInterval, cfg.QuotaSafetyMargin, log)
+	if cfg.DeepLApiKey != "" && !cfg.MockProviderOverride {
+		quotaMonitor.AddSource(translation.ProviderDeepL, deepLClient)
+	}
+	monitorCtx, stopMonitor := context.WithCancel(context.Background())
+	defer stopMonitor()
+	go quotaMonitor.Start(monitorCtx)
+
+	modelCatalog := services.NewModelCatalogService(modelRepo, providers, cfg.ModelCatalogRefresh, log)
+	if err := modelCatalog.Load(monitorCtx, translation.Catalog); err != nil {
+		log.Error().Err(err).Msg("failed to load model catalog, serving built-in models")
+	}
+	go modelCatalog.Start(monitorCtx)
 
 	userService := services.NewUserService(userRepo, cfg.JWTSecret, cfg.AccessTokenTTL, cfg.RefreshTokenTTL)
-	translationService := services.NewTranslationService(translationRepo, fileRepo, storageProvider, queueClient, deepLClient, otranslatorClient, cfg.FileRetention, log)
+	glossaryService := services.NewGlossaryService(glossaryRepo, deepLClient, log)
+	languageService := services.NewLanguageService(providers, redisCache, cfg.LanguagesCacheTTL, log)
+	memoryWeights := translation.MatchWeights{
+		translation.MatchRepetition: cfg.TMRepetitionRate,
+		translation.MatchExact:      cfg.TMExactMatchRate,
+		translation.MatchFuzzy95:    cfg.TMFuzzy95Rate,
+		translation.MatchFuzzy85:    cfg.TMFuzzy85Rate,
+		translation.MatchFuzzy75:    cfg.TMFuzzy75Rate,
+	}
+	memoryService := services.NewMemoryService(memoryRepo, memoryWeights, cfg.TMFuzzyCandidates, log)
+	pricingService := services.NewPricingService(priceListRepo, log)
+	translationService := services.NewTranslationService(translationRepo, orderRepo, fileRepo, storageProvider, queueClient, providers, glossaryService, languageService, memoryService, pricingService, quotaMonitor, cfg.FileRetention, log)
 
 	stripeClient := payment.NewStripeClient(cfg.StripeSecretKey, cfg.StripeCurrency)
 	paymentService := services.NewPaymentService(paymentRepo, userRepo, translationRepo, translationService, stripeClient, cfg.StripePremiumPriceID)
+	segmentService := services.NewSegmentService(segmentEditRepo, translationService, memoryService, log)
 
-	handler := apphttp.NewHandler(cfg, userService, translationService, paymentService)
+	handler := apphttp.NewHandler(cfg, userService, translationService, paymentService, glossaryService, memoryService, segmentService, modelCatalog, pricingService, languageService, quotaMonitor)
 	router := apphttp.NewRouter(handler, cfg.AllowOrigins, 180)
 	apphttp.AttachStatic(router, filepath.Join("public"))
 
@@ -71,7 +157,7 @@ func main() {
 		Handler: router,
 	}
 
-	workerService, err := worker.New(cfg.RedisURL, translationRepo, userRepo, fileRepo, storageProvider, translationService, paymentService, deepLClient, otranslatorClient, log)
+	workerService, err := worker.New(cfg.RedisURL, translationRepo, userRepo, fileRepo, storageProvider, translationService, memoryService, paymentService, providers, failoverPolicy, log)
 	if err != nil {
 		log.Fatal().Err(err).Msg("failed to init worker")
 	}
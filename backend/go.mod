@@ -20,6 +20,7 @@ require (
     github.com/jung-kurt/gofpdf v1.21.0
     github.com/ledongthuc/pdf v0.0.0-20220302135154-068b6a2dc1a0
     github.com/pressly/goose/v3 v3.16.0
+    github.com/redis/go-redis/v9 v9.7.0
     github.com/rs/zerolog v1.33.0
     github.com/stripe/stripe-go/v75 v75.7.0
     golang.org/x/crypto v0.26.0
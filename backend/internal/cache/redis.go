@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisCache stores JSON values in Redis.
+type RedisCache struct {
+	client *redis.Client
+}
+
+// NewRedisCache connects to Redis at redisURL.
+func NewRedisCache(redisURL string) (*RedisCache, error) {
+	opts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse redis url: %w", err)
+	}
+	return &RedisCache{client: redis.NewClient(opts)}, nil
+}
+
+// SetJSON stores value under key for ttl.
+func (c *RedisCache) SetJSON(key string, value interface{}, ttl time.Duration) error {
+	body, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return c.client.Set(context.Background(), key, body, ttl).Err()
+}
+
+// GetJSON loads key into dest. It reports false when the key is missing.
+func (c *RedisCache) GetJSON(key string, dest interface{}) (bool, error) {
+	body, err := c.client.Get(context.Background(), key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(body, dest); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Close closes the underlying connection pool.
+func (c *RedisCache) Close() error {
+	return c.client.Close()
+}
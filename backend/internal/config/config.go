@@ -21,6 +21,48 @@ type Config struct {
 	OTranslatorKey  string `env:"OTRANSLATOR_API_KEY"`
 	OTranslatorBase string `env:"OTRANSLATOR_BASE_URL" envDefault:"https://api.otranslator.ai"`
 
+	ProviderMaxAttempts    int           `env:"PROVIDER_MAX_ATTEMPTS" envDefault:"4"`
+	ProviderRetryBaseDelay time.Duration `env:"PROVIDER_RETRY_BASE_DELAY" envDefault:"500ms"`
+	ProviderRetryMaxDelay  time.Duration `env:"PROVIDER_RETRY_MAX_DELAY" envDefault:"30s"`
+
+	LibreTranslateEnabled    bool          `env:"LIBRETRANSLATE_ENABLED" envDefault:"false"`
+	LibreTranslateURL        string        `env:"LIBRETRANSLATE_URL" envDefault:"http://localhost:5000"`
+	LibreTranslateKey        string        `env:"LIBRETRANSLATE_API_KEY"`
+	LibreTranslateTimeout    time.Duration `env:"LIBRETRANSLATE_TIMEOUT" envDefault:"2m"`
+	LibreTranslateChunkChars int           `env:"LIBRETRANSLATE_CHUNK_CHARS" envDefault:"2000"`
+
+	LLMEnabled         bool          `env:"LLM_ENABLED" envDefault:"false"`
+	LLMBaseURL         string        `env:"LLM_BASE_URL" envDefault:"https://api.openai.com/v1"`
+	LLMApiKey          string        `env:"LLM_API_KEY"`
+	LLMModel           string        `env:"LLM_MODEL" envDefault:"gpt-4o-mini"`
+	LLMTimeout         time.Duration `env:"LLM_TIMEOUT" envDefault:"2m"`
+	LLMChunkChars      int           `env:"LLM_CHUNK_CHARS" envDefault:"3000"`
+	LLMContextSegments int           `env:"LLM_CONTEXT_SEGMENTS" envDefault:"5"`
+
+	QuotaPollInterval time.Duration `env:"QUOTA_POLL_INTERVAL" envDefault:"5m"`
+	QuotaSafetyMargin int64         `env:"QUOTA_SAFETY_MARGIN" envDefault:"50000"`
+
+	LanguagesCacheTTL time.Duration `env:"LANGUAGES_CACHE_TTL" envDefault:"24h"`
+
+	ModelCatalogRefresh time.Duration `env:"MODEL_CATALOG_REFRESH" envDefault:"1m"` // reload interval of the model catalog
+
+	// Share of the regular price charged per translation memory match band.
+	TMRepetitionRate  float64 `env:"TM_REPETITION_RATE" envDefault:"0.1"`
+	TMExactMatchRate  float64 `env:"TM_EXACT_MATCH_RATE" envDefault:"0.1"`
+	TMFuzzy95Rate     float64 `env:"TM_FUZZY_95_RATE" envDefault:"0.3"`
+	TMFuzzy85Rate     float64 `env:"TM_FUZZY_85_RATE" envDefault:"0.6"`
+	TMFuzzy75Rate     float64 `env:"TM_FUZZY_75_RATE" envDefault:"0.8"`
+	TMFuzzyCandidates int     `env:"TM_FUZZY_CANDIDATES" envDefault:"5000"`
+
+	FailoverEnabled bool     `env:"FAILOVER_ENABLED" envDefault:"true"`
+	FailoverOn      []string `env:"FAILOVER_ON" envSeparator:"," envDefault:"server_error,quota_exceeded,timeout,unreachable"`
+
+	MockProviderEnabled     bool          `env:"MOCK_PROVIDER_ENABLED" envDefault:"false"`
+	MockProviderOverride    bool          `env:"MOCK_PROVIDER_OVERRIDE" envDefault:"false"` // route every model to the mock provider
+	MockProviderLatency     time.Duration `env:"MOCK_PROVIDER_LATENCY" envDefault:"0s"`
+	MockProviderFailureRate float64       `env:"MOCK_PROVIDER_FAILURE_RATE" envDefault:"0"`
+	MockProviderSeed        int64         `env:"MOCK_PROVIDER_SEED" envDefault:"1"`
+
 	JWTSecret       string        `env:"JWT_SECRET,required"`
 	AccessTokenTTL  time.Duration `env:"ACCESS_TOKEN_TTL" envDefault:"1h"`
 	RefreshTokenTTL time.Duration `env:"REFRESH_TOKEN_TTL" envDefault:"720h"`
@@ -43,6 +85,9 @@ type Config struct {
 
 	AllowOrigins []string `env:"ALLOW_ORIGINS" envSeparator:"," envDefault:"*"`
 
+	// AdminUserIDs are the IDs of the users allowed on the admin API.
+	AdminUserIDs []string `env:"ADMIN_USER_IDS" envSeparator:","`
+
 	EnableDebug bool `env:"ENABLE_DEBUG" envDefault:"false"`
 }
 
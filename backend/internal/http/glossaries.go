@@ -0,0 +1,129 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	appmiddleware "github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/middleware"
+	"github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/services"
+)
+
+type createGlossaryRequest struct {
+	Name          string `json:"name"`
+	SourceLang    string `json:"sourceLang"`
+	TargetLang    string `json:"targetLang"`
+	Entries       string `json:"entries"`
+	EntriesFormat string `json:"entriesFormat"`
+}
+
+// handleCreateGlossary accepts JSON or a multipart form with a CSV/TSV file.
+func (h *Handler) handleCreateGlossary(w http.ResponseWriter, r *http.Request) {
+	claims := appmiddleware.MustUserClaims(r)
+	if claims == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req createGlossaryRequest
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(10 << 20); err != nil {
+			respondError(w, http.StatusBadRequest, "invalid multipart form")
+			return
+		}
+		req.Name = r.FormValue("name")
+		req.SourceLang = r.FormValue("sourceLang")
+		req.TargetLang = r.FormValue("targetLang")
+		req.EntriesFormat = r.FormValue("entriesFormat")
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "file is required")
+			return
+		}
+		defer file.Close()
+		data, err := io.ReadAll(file)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "failed to read file")
+			return
+		}
+		req.Entries = string(data)
+		if req.EntriesFormat == "" {
+			req.EntriesFormat = strings.TrimPrefix(strings.ToLower(filepath.Ext(header.Filename)), ".")
+		}
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid payload")
+		return
+	}
+
+	glossary, err := h.glossaryService.Create(r.Context(), services.CreateGlossaryInput{
+		UserID:        claims.UserID,
+		Name:          req.Name,
+		SourceLang:    req.SourceLang,
+		TargetLang:    req.TargetLang,
+		Entries:       req.Entries,
+		EntriesFormat: req.EntriesFormat,
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrGlossaryProvider) {
+			respondError(w, http.StatusBadGateway, err.Error())
+			return
+		}
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusCreated, glossary)
+}
+
+func (h *Handler) handleListGlossaries(w http.ResponseWriter, r *http.Request) {
+	claims := appmiddleware.MustUserClaims(r)
+	if claims == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	glossaries, err := h.glossaryService.List(r.Context(), claims.UserID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to load glossaries")
+		return
+	}
+	respondJSON(w, http.StatusOK, glossaries)
+}
+
+func (h *Handler) handleGetGlossary(w http.ResponseWriter, r *http.Request) {
+	claims := appmiddleware.MustUserClaims(r)
+	if claims == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	details, err := h.glossaryService.Details(r.Context(), claims.UserID, chi.URLParam(r, "id"))
+	if err != nil {
+		if errors.Is(err, services.ErrGlossaryNotFound) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, details)
+}
+
+func (h *Handler) handleDeleteGlossary(w http.ResponseWriter, r *http.Request) {
+	claims := appmiddleware.MustUserClaims(r)
+	if claims == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	if err := h.glossaryService.Delete(r.Context(), claims.UserID, chi.URLParam(r, "id")); err != nil {
+		if errors.Is(err, services.ErrGlossaryNotFound) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
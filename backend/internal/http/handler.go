@@ -2,12 +2,16 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
+	"expvar"
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/stripe/stripe-go/v75"
 
 	"github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/auth"
 	"github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/config"
@@ -23,6 +27,13 @@ type Handler struct {
 	userService         *services.UserService
 	translationSvc      *services.TranslationService
 	paymentService      *services.PaymentService
+	glossaryService     *services.GlossaryService
+	memoryService       *services.MemoryService
+	segmentService      *services.SegmentService
+	modelCatalog        *services.ModelCatalogService
+	pricingService      *services.PricingService
+	languageService     *services.LanguageService
+	quotaMonitor        *services.QuotaMonitor
 	stripeWebhookSecret string
 	deepl               translation.DeepLClient
 	redis               RedisClient
@@ -41,12 +52,19 @@ type Logger interface {
 }
 
 // NewHandler constructs HTTP handler.
-func NewHandler(cfg *config.Config, userSvc *services.UserService, translationSvc *services.TranslationService, paymentSvc *services.PaymentService) *Handler {
+func NewHandler(cfg *config.Config, userSvc *services.UserService, translationSvc *services.TranslationService, paymentSvc *services.PaymentService, glossarySvc *services.GlossaryService, memorySvc *services.MemoryService, segmentSvc *services.SegmentService, modelCatalog *services.ModelCatalogService, pricingSvc *services.PricingService, languageSvc *services.LanguageService, quotaMonitor *services.QuotaMonitor) *Handler {
 	return &Handler{
 		cfg:                 cfg,
 		userService:         userSvc,
 		translationSvc:      translationSvc,
 		paymentService:      paymentSvc,
+		glossaryService:     glossarySvc,
+		memoryService:       memorySvc,
+		segmentService:      segmentSvc,
+		modelCatalog:        modelCatalog,
+		pricingService:      pricingSvc,
+		languageService:     languageSvc,
+		quotaMonitor:        quotaMonitor,
 		stripeWebhookSecret: cfg.StripeWebhookSecret,
 	}
 }
@@ -71,6 +89,7 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 		})
 
 		r.Get("/models", h.handleListModels)
+		r.Get("/languages", h.handleListLanguages)
 
 		r.Group(func(r chi.Router) {
 			r.Use(appmiddleware.AuthMiddleware(h.cfg.JWTSecret))
@@ -78,13 +97,38 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 			r.Get("/translations", h.handleListTranslations)
 			r.Get("/translations/{id}", h.handleGetTranslation)
 			r.Get("/translations/{id}/download", h.handleDownloadTranslation)
+			r.Post("/translations/{id}/review", h.handleUploadReview)
+			r.Get("/translations/{id}/segments", h.handleListSegments)
+			r.Patch("/translations/{id}/segments", h.handleEditSegments)
 			r.Get("/translations/{id}/events", h.handleTranslationEvents)
+			r.Get("/orders/{id}", h.handleGetOrder)
+
+			r.Post("/glossaries", h.handleCreateGlossary)
+			r.Get("/glossaries", h.handleListGlossaries)
+			r.Get("/glossaries/{id}", h.handleGetGlossary)
+			r.Delete("/glossaries/{id}", h.handleDeleteGlossary)
+
+			r.Post("/memory/tmx", h.handleImportTMX)
+			r.Get("/memory/tmx", h.handleExportTMX)
 
 			r.Post("/payments/translations", h.handleCreateTranslationPayment)
 			r.Post("/payments/subscription", h.handleCreateSubscriptionPayment)
 		})
 
 		r.Post("/payments/webhook", h.handleStripeWebhook)
+
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(appmiddleware.AuthMiddleware(h.cfg.JWTSecret))
+			r.Use(appmiddleware.AdminOnly(h.cfg.AdminUserIDs))
+			r.Get("/usage", h.handleProviderUsage)
+			r.Get("/models", h.handleAdminListModels)
+			r.Post("/models", h.handleAdminCreateModel)
+			r.Put("/models/{key}", h.handleAdminUpdateModel)
+			r.Delete("/models/{key}", h.handleAdminDeleteModel)
+			r.Get("/price-lists", h.handleAdminListPriceLists)
+			r.Post("/price-lists", h.handleAdminCreatePriceList)
+			r.Method(http.MethodGet, "/metrics", expvar.Handler())
+		})
 	})
 }
 
@@ -185,6 +229,19 @@ func (h *Handler) handleListModels(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, models)
 }
 
+func (h *Handler) handleListLanguages(w http.ResponseWriter, r *http.Request) {
+	languages, err := h.languageService.List(r.Context(), r.URL.Query().Get("model"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, languages)
+}
+
+func (h *Handler) handleProviderUsage(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, h.quotaMonitor.Snapshot())
+}
+
 func (h *Handler) handleCreateTranslation(w http.ResponseWriter, r *http.Request) {
 	claims := appmiddleware.MustUserClaims(r)
 	if claims == nil {
@@ -214,7 +271,9 @@ func (h *Handler) handleCreateTranslation(w http.ResponseWriter, r *http.Request
 	}
 	sourceLang := r.FormValue("sourceLang")
 	targetLang := r.FormValue("targetLang")
+	targetLangs := splitFormList(r.MultipartForm.Value["targetLangs"])
 	modelKey := r.FormValue("modelKey")
+	glossaryID := r.FormValue("glossaryId")
 	optionsValue := r.FormValue("options")
 	stripTagsValue := r.FormValue("stripTags")
 	stripTags := stripTagsValue == "true"
@@ -228,7 +287,7 @@ func (h *Handler) handleCreateTranslation(w http.ResponseWriter, r *http.Request
 
 	contentType := services.DetermineContentType(header.Filename)
 
-	result, err := h.translationSvc.CreateTranslation(r.Context(), services.CreateTranslationInput{
+	input := services.CreateTranslationInput{
 		User:        user,
 		Filename:    header.Filename,
 		Data:        data,
@@ -236,18 +295,102 @@ func (h *Handler) handleCreateTranslation(w http.ResponseWriter, r *http.Request
 		SourceLang:  sourceLang,
 		TargetLang:  targetLang,
 		ModelKey:    modelKey,
+		GlossaryID:  glossaryID,
 		Options:     options,
 		StripTags:   stripTags,
-	})
+	}
+	if len(targetLangs) > 1 {
+		h.createOrder(w, r, input, targetLangs)
+		return
+	}
+	if len(targetLangs) == 1 && input.TargetLang == "" {
+		input.TargetLang = targetLangs[0]
+	}
+
+	result, err := h.translationSvc.CreateTranslation(r.Context(), input)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		respondCreateError(w, err)
 		return
 	}
 
-	respondJSON(w, http.StatusCreated, map[string]interface{}{
+	response := map[string]interface{}{
 		"translation": result.Translation,
 		"model":       result.Model.ModelDescriptor,
-	})
+	}
+	if len(result.Warnings) > 0 {
+		response["warnings"] = result.Warnings
+	}
+	if result.Analysis != nil {
+		response["analysis"] = result.Analysis
+	}
+	respondJSON(w, http.StatusCreated, response)
+}
+
+// respondCreateError writes the status of a failed translation or order
+// creation.
+func respondCreateError(w http.ResponseWriter, err error) {
+	if errors.Is(err, services.ErrQuotaExhausted) {
+		respondError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	if errors.Is(err, services.ErrDocumentTooLarge) {
+		respondError(w, http.StatusRequestEntityTooLarge, err.Error())
+		return
+	}
+	var optionErr *translation.OptionError
+	if errors.As(err, &optionErr) {
+		respondJSON(w, http.StatusBadRequest, map[string]interface{}{"error": err.Error(), "fields": optionErr.Fields})
+		return
+	}
+	respondError(w, http.StatusBadRequest, err.Error())
+}
+
+// createOrder creates one child translation per target language.
+func (h *Handler) createOrder(w http.ResponseWriter, r *http.Request, input services.CreateTranslationInput, targetLangs []string) {
+	result, err := h.translationSvc.CreateOrder(r.Context(), input, targetLangs)
+	if err != nil {
+		respondCreateError(w, err)
+		return
+	}
+	response := map[string]interface{}{
+		"order":        result.Order,
+		"translations": result.Translations,
+		"model":        result.Model.ModelDescriptor,
+	}
+	if len(result.Warnings) > 0 {
+		response["warnings"] = result.Warnings
+	}
+	if len(result.Analyses) > 0 {
+		response["analyses"] = result.Analyses
+	}
+	respondJSON(w, http.StatusCreated, response)
+}
+
+// splitFormList accepts repeated form values as well as comma separated ones.
+func splitFormList(values []string) []string {
+	var result []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				result = append(result, item)
+			}
+		}
+	}
+	return result
+}
+
+func (h *Handler) handleGetOrder(w http.ResponseWriter, r *http.Request) {
+	claims := appmiddleware.MustUserClaims(r)
+	if claims == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	order, err := h.translationSvc.GetOrder(r.Context(), chi.URLParam(r, "id"))
+	if err != nil || order.UserID != claims.UserID {
+		respondError(w, http.StatusNotFound, "order not found")
+		return
+	}
+	respondJSON(w, http.StatusOK, order)
 }
 
 func (h *Handler) handleListTranslations(w http.ResponseWriter, r *http.Request) {
@@ -302,8 +445,12 @@ func (h *Handler) handleDownloadTranslation(w http.ResponseWriter, r *http.Reque
 		respondError(w, http.StatusConflict, "translation not ready")
 		return
 	}
-	reader, filename, err := h.translationSvc.OpenTranslatedFile(r.Context(), translation.ID)
+	reader, filename, err := h.translationSvc.OpenTranslatedFile(r.Context(), translation.ID, r.URL.Query().Get("format"))
 	if err != nil {
+		if errors.Is(err, services.ErrUnsupportedFormat) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -316,6 +463,89 @@ func (h *Handler) handleDownloadTranslation(w http.ResponseWriter, r *http.Reque
 	}
 }
 
+// handleUploadReview applies a reviewed XLIFF to a completed translation.
+func (h *Handler) handleUploadReview(w http.ResponseWriter, r *http.Request) {
+	claims := appmiddleware.MustUserClaims(r)
+	if claims == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	id := chi.URLParam(r, "id")
+	translation, err := h.translationSvc.GetTranslation(r.Context(), id)
+	if err != nil || translation.UserID != claims.UserID {
+		respondError(w, http.StatusNotFound, "translation not found")
+		return
+	}
+	if translation.Status != models.TranslationCompleted {
+		respondError(w, http.StatusConflict, "translation not ready")
+		return
+	}
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid multipart form")
+		return
+	}
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "file is required")
+		return
+	}
+	defer file.Close()
+	record, err := h.translationSvc.ImportReviewedXLIFF(r.Context(), translation.ID, file)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusCreated, record)
+}
+
+func (h *Handler) handleListSegments(w http.ResponseWriter, r *http.Request) {
+	claims := appmiddleware.MustUserClaims(r)
+	if claims == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	translation, err := h.translationSvc.GetTranslation(r.Context(), chi.URLParam(r, "id"))
+	if err != nil || translation.UserID != claims.UserID {
+		respondError(w, http.StatusNotFound, "translation not found")
+		return
+	}
+	segments, err := h.segmentService.List(r.Context(), translation)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]interface{}{"segments": segments})
+}
+
+type editSegmentsRequest struct {
+	Segments []services.SegmentEditInput `json:"segments"`
+}
+
+// handleEditSegments applies post-edits and regenerates the document.
+func (h *Handler) handleEditSegments(w http.ResponseWriter, r *http.Request) {
+	claims := appmiddleware.MustUserClaims(r)
+	if claims == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	translation, err := h.translationSvc.GetTranslation(r.Context(), chi.URLParam(r, "id"))
+	if err != nil || translation.UserID != claims.UserID {
+		respondError(w, http.StatusNotFound, "translation not found")
+		return
+	}
+	var req editSegmentsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid payload")
+		return
+	}
+	segments, err := h.segmentService.Edit(r.Context(), translation, claims.UserID, req.Segments)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]interface{}{"segments": segments})
+}
+
 func (h *Handler) handleTranslationEvents(w http.ResponseWriter, r *http.Request) {
 	f, ok := w.(http.Flusher)
 	if !ok {
@@ -370,6 +600,7 @@ func (h *Handler) handleCreateTranslationPayment(w http.ResponseWriter, r *http.
 	}
 	var req struct {
 		TranslationID string `json:"translationId"`
+		OrderID       string `json:"orderId"`
 		SuccessURL    string `json:"successUrl"`
 		CancelURL     string `json:"cancelUrl"`
 	}
@@ -377,8 +608,18 @@ func (h *Handler) handleCreateTranslationPayment(w http.ResponseWriter, r *http.
 		respondError(w, http.StatusBadRequest, "invalid payload")
 		return
 	}
-	session, err := h.paymentService.StartTranslationCheckout(r.Context(), claims.UserID, req.TranslationID, req.SuccessURL, req.CancelURL)
+	var session *stripe.CheckoutSession
+	var err error
+	if req.OrderID != "" {
+		session, err = h.paymentService.StartOrderCheckout(r.Context(), claims.UserID, req.OrderID, req.SuccessURL, req.CancelURL)
+	} else {
+		session, err = h.paymentService.StartTranslationCheckout(r.Context(), claims.UserID, req.TranslationID, req.SuccessURL, req.CancelURL)
+	}
 	if err != nil {
+		if errors.Is(err, services.ErrQuotaExhausted) {
+			respondError(w, http.StatusServiceUnavailable, err.Error())
+			return
+		}
 		respondError(w, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -0,0 +1,55 @@
+package http
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"time"
+
+	appmiddleware "github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/middleware"
+)
+
+// handleImportTMX stores the segments of an uploaded TMX file in the user's
+// translation memory.
+func (h *Handler) handleImportTMX(w http.ResponseWriter, r *http.Request) {
+	claims := appmiddleware.MustUserClaims(r)
+	if claims == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid multipart form")
+		return
+	}
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "file is required")
+		return
+	}
+	defer file.Close()
+
+	result, err := h.memoryService.ImportTMX(r.Context(), claims.UserID, file, r.FormValue("sourceLang"), r.FormValue("targetLang"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusCreated, result)
+}
+
+// handleExportTMX downloads the user's completed translations as TMX.
+func (h *Handler) handleExportTMX(w http.ResponseWriter, r *http.Request) {
+	claims := appmiddleware.MustUserClaims(r)
+	if claims == nil {
+		respondError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	var buf bytes.Buffer
+	if err := h.translationSvc.ExportTMX(r.Context(), claims.UserID, r.URL.Query().Get("sourceLang"), r.URL.Query().Get("targetLang"), &buf); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	filename := "translation-memory-" + time.Now().UTC().Format("20060102") + ".tmx"
+	w.Header().Set("Content-Disposition", "attachment; filename="+strconv.Quote(filename))
+	w.Header().Set("Content-Type", "application/x-tmx+xml")
+	_, _ = w.Write(buf.Bytes())
+}
@@ -0,0 +1,69 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/models"
+	"github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/services"
+)
+
+func (h *Handler) handleAdminListModels(w http.ResponseWriter, r *http.Request) {
+	catalog, err := h.modelCatalog.List(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to load models")
+		return
+	}
+	respondJSON(w, http.StatusOK, catalog)
+}
+
+func (h *Handler) handleAdminCreateModel(w http.ResponseWriter, r *http.Request) {
+	var req models.CatalogModel
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid payload")
+		return
+	}
+	model, err := h.modelCatalog.Create(r.Context(), req)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusCreated, model)
+}
+
+func (h *Handler) handleAdminUpdateModel(w http.ResponseWriter, r *http.Request) {
+	var req models.CatalogModel
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid payload")
+		return
+	}
+	model, err := h.modelCatalog.Update(r.Context(), chi.URLParam(r, "key"), req)
+	if err != nil {
+		if errors.Is(err, services.ErrModelNotFound) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, model)
+}
+
+func (h *Handler) handleAdminDeleteModel(w http.ResponseWriter, r *http.Request) {
+	if err := h.modelCatalog.Delete(r.Context(), chi.URLParam(r, "key")); err != nil {
+		if errors.Is(err, services.ErrModelNotFound) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrModelInUse) {
+			respondError(w, http.StatusConflict, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
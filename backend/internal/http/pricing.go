@@ -0,0 +1,31 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/models"
+)
+
+func (h *Handler) handleAdminListPriceLists(w http.ResponseWriter, r *http.Request) {
+	lists, err := h.pricingService.ListPriceLists(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "failed to load price lists")
+		return
+	}
+	respondJSON(w, http.StatusOK, lists)
+}
+
+func (h *Handler) handleAdminCreatePriceList(w http.ResponseWriter, r *http.Request) {
+	var req models.PriceList
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid payload")
+		return
+	}
+	list, err := h.pricingService.CreatePriceList(r.Context(), req)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusCreated, list)
+}
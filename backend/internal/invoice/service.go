@@ -41,18 +41,30 @@ func Generate(meta models.InvoiceMetadata) ([]byte, error) {
 	pdf.Ln(-1)
 
 	pdf.SetFont("Helvetica", "", 12)
-	pricePer := float64(meta.Model.PricePer1860)
-	unitPrice := fmt.Sprintf("%.2f %s", pricePer, meta.Currency)
+	unitPrice := fmt.Sprintf("%.2f %s", meta.UnitPrice, meta.Currency)
 	total := float64(meta.GrossAmountCents) / 100.0
 	totalFormatted := fmt.Sprintf("%.2f %s", total, meta.Currency)
 
-	description := fmt.Sprintf("%s (%s → %s)", meta.Model.DisplayName, meta.Translation.SourceLang, meta.Translation.TargetLang)
+	description := fmt.Sprintf("%s (%s → %s)", meta.Model.DisplayName, meta.Translation.EffectiveSourceLang(), meta.Translation.TargetLang)
 	pdf.CellFormat(95, 8, description, "1", 0, "L", false, 0, "")
 	pdf.CellFormat(30, 8, fmt.Sprintf("%d", meta.Translation.CharacterCount), "1", 0, "C", false, 0, "")
 	pdf.CellFormat(30, 8, unitPrice, "1", 0, "C", false, 0, "")
 	pdf.CellFormat(35, 8, totalFormatted, "1", 0, "C", false, 0, "")
 	pdf.Ln(-1)
 
+	if meta.Discount > 0 {
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(190, 6, fmt.Sprintf("inkl. %.0f%% Premium-Rabatt", meta.Discount*100), "", 0, "L", false, 0, "")
+		pdf.Ln(-1)
+		pdf.SetFont("Helvetica", "", 12)
+	}
+	if meta.PriceListVersion != nil {
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(190, 6, fmt.Sprintf("Preisliste Version %d", *meta.PriceListVersion), "", 0, "L", false, 0, "")
+		pdf.Ln(-1)
+		pdf.SetFont("Helvetica", "", 12)
+	}
+
 	pdf.Ln(8)
 	pdf.CellFormat(125, 6, "Zwischensumme", "", 0, "R", false, 0, "")
 	pdf.CellFormat(35, 6, fmt.Sprintf("%.2f %s", float64(meta.NetAmountCents)/100, meta.Currency), "", 0, "R", false, 0, "")
@@ -76,8 +88,14 @@ func Generate(meta models.InvoiceMetadata) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-// BuildMetadata constructs invoice metadata from translation entity.
+// BuildMetadata constructs invoice metadata from translation entity. Prices
+// come from the snapshot taken when the translation was quoted; translations
+// created before snapshots existed fall back to the current model price.
 func BuildMetadata(user models.User, translation models.Translation, model models.ModelDescriptor) models.InvoiceMetadata {
+	unitPrice := translation.UnitPrice
+	if unitPrice == 0 {
+		unitPrice = model.PricePer1860
+	}
 	netAmount := translation.PriceCents
 	tax := int64(float64(netAmount) * 0.19 / 1.19)
 	gross := netAmount
@@ -90,6 +108,9 @@ func BuildMetadata(user models.User, translation models.Translation, model model
 		TaxAmountCents:   tax,
 		GrossAmountCents: gross,
 		Currency:         translation.Currency,
+		PriceListVersion: translation.PriceListVersion,
+		UnitPrice:        unitPrice,
+		Discount:         translation.PriceDiscount,
 		IssuedAt:         time.Now(),
 	}
 }
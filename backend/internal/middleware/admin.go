@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+)
+
+// AdminOnly restricts access to the configured admin user IDs, compared
+// exactly. Usernames are not used: anyone can register one that differs
+// from an admin's only in case. It must run after AuthMiddleware.
+func AdminOnly(userIDs []string) func(http.Handler) http.Handler {
+	allowed := make(map[string]struct{}, len(userIDs))
+	for _, id := range userIDs {
+		if id = strings.TrimSpace(id); id != "" {
+			allowed[id] = struct{}{}
+		}
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims := MustUserClaims(r)
+			if claims == nil {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			if _, ok := allowed[claims.UserID]; !ok {
+				http.Error(w, "forbidden", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -54,3 +54,70 @@ func (j *JSONB) Scan(src interface{}) error {
 		return fmt.Errorf("unsupported type for JSONB: %T", src)
 	}
 }
+
+// StringList is a helper type for jsonb arrays of strings.
+type StringList []string
+
+// Value implements driver.Valuer.
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(l)
+}
+
+// Scan implements sql.Scanner.
+func (l *StringList) Scan(src interface{}) error {
+	return scanJSON(src, l)
+}
+
+// StringMap is a helper type for jsonb objects with string values.
+type StringMap map[string]string
+
+// Value implements driver.Valuer.
+func (m StringMap) Value() (driver.Value, error) {
+	if m == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(m)
+}
+
+// Scan implements sql.Scanner.
+func (m *StringMap) Scan(src interface{}) error {
+	return scanJSON(src, m)
+}
+
+// ModelRoutes is a helper type for jsonb arrays of provider routes.
+type ModelRoutes []ModelRoute
+
+// Value implements driver.Valuer.
+func (r ModelRoutes) Value() (driver.Value, error) {
+	if r == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(r)
+}
+
+// Scan implements sql.Scanner.
+func (r *ModelRoutes) Scan(src interface{}) error {
+	return scanJSON(src, r)
+}
+
+func scanJSON(src interface{}, dest interface{}) error {
+	switch data := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		if len(data) == 0 {
+			return nil
+		}
+		return json.Unmarshal(data, dest)
+	case string:
+		if data == "" {
+			return nil
+		}
+		return json.Unmarshal([]byte(data), dest)
+	default:
+		return fmt.Errorf("unsupported type for jsonb: %T", src)
+	}
+}
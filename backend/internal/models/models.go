@@ -27,6 +27,7 @@ type FileKind string
 const (
 	FileKindSource     FileKind = "source"
 	FileKindTranslated FileKind = "translated"
+	FileKindReviewed   FileKind = "reviewed"
 	FileKindInvoice    FileKind = "invoice"
 )
 
@@ -45,24 +46,97 @@ type User struct {
 
 // Translation describes a translation request and result.
 type Translation struct {
-	ID                 string            `db:"id" json:"id"`
-	UserID             string            `db:"user_id" json:"userId"`
-	SourceLang         string            `db:"source_lang" json:"sourceLang"`
-	TargetLang         string            `db:"target_lang" json:"targetLang"`
-	ModelKey           string            `db:"model_key" json:"modelKey"`
-	CharacterCount     int               `db:"character_count" json:"characterCount"`
-	PriceCents         int64             `db:"price_cents" json:"priceCents"`
-	Currency           string            `db:"currency" json:"currency"`
-	Options            JSONB             `db:"options" json:"options"`
-	Status             TranslationStatus `db:"status" json:"status"`
-	QueueTaskID        string            `db:"queue_task_id" json:"queueTaskId"`
-	OriginalFilename   string            `db:"original_filename" json:"originalFilename"`
-	TranslatedFilename *string           `db:"translated_filename" json:"translatedFilename,omitempty"`
-	DeleteAfter        *time.Time        `db:"delete_after" json:"deleteAfter,omitempty"`
-	FailureReason      *string           `db:"failure_reason" json:"failureReason,omitempty"`
-	CreatedAt          time.Time         `db:"created_at" json:"createdAt"`
-	UpdatedAt          time.Time         `db:"updated_at" json:"updatedAt"`
-	CompletedAt        *time.Time        `db:"completed_at" json:"completedAt,omitempty"`
+	ID                  string            `db:"id" json:"id"`
+	UserID              string            `db:"user_id" json:"userId"`
+	OrderID             *string           `db:"order_id" json:"orderId,omitempty"`
+	SourceLang          string            `db:"source_lang" json:"sourceLang"`
+	DetectedSourceLang  *string           `db:"detected_source_lang" json:"detectedSourceLang,omitempty"`
+	DetectionConfidence *float64          `db:"detection_confidence" json:"detectionConfidence,omitempty"`
+	TargetLang          string            `db:"target_lang" json:"targetLang"`
+	ModelKey            string            `db:"model_key" json:"modelKey"`
+	CharacterCount      int               `db:"character_count" json:"characterCount"`
+	TMMatchedCharacters int               `db:"tm_matched_characters" json:"tmMatchedCharacters"`
+	PriceCents          int64             `db:"price_cents" json:"priceCents"`
+	Currency            string            `db:"currency" json:"currency"`
+	PriceListVersion    *int              `db:"price_list_version" json:"priceListVersion,omitempty"`
+	UnitPrice           float64           `db:"unit_price" json:"unitPrice"`
+	PriceDiscount       float64           `db:"price_discount" json:"priceDiscount"`
+	Options             JSONB             `db:"options" json:"options"`
+	Status              TranslationStatus `db:"status" json:"status"`
+	QueueTaskID         string            `db:"queue_task_id" json:"queueTaskId"`
+	OriginalFilename    string            `db:"original_filename" json:"originalFilename"`
+	TranslatedFilename  *string           `db:"translated_filename" json:"translatedFilename,omitempty"`
+	DeleteAfter         *time.Time        `db:"delete_after" json:"deleteAfter,omitempty"`
+	FailureReason       *string           `db:"failure_reason" json:"failureReason,omitempty"`
+	ProcessedProvider   *string           `db:"processed_provider" json:"processedProvider,omitempty"`
+	ProcessedEngine     *string           `db:"processed_engine" json:"processedEngine,omitempty"`
+	ProviderJobProvider *string           `db:"provider_job_provider" json:"-"`
+	ProviderJobEngine   *string           `db:"provider_job_engine" json:"-"`
+	ProviderJobID       *string           `db:"provider_job_id" json:"-"`
+	ProviderJobKey      *string           `db:"provider_job_key" json:"-"`
+	CreatedAt           time.Time         `db:"created_at" json:"createdAt"`
+	UpdatedAt           time.Time         `db:"updated_at" json:"updatedAt"`
+	CompletedAt         *time.Time        `db:"completed_at" json:"completedAt,omitempty"`
+}
+
+// EffectiveSourceLang returns the selected source language, or the detected
+// one when the user relied on auto-detection.
+func (t Translation) EffectiveSourceLang() string {
+	if t.SourceLang == "" && t.DetectedSourceLang != nil {
+		return *t.DetectedSourceLang
+	}
+	return t.SourceLang
+}
+
+// OrderStatus summarises the translations of an order.
+type OrderStatus string
+
+const (
+	OrderPending            OrderStatus = "pending"
+	OrderProcessing         OrderStatus = "processing"
+	OrderCompleted          OrderStatus = "completed"
+	OrderPartiallyCompleted OrderStatus = "partially_completed"
+	OrderFailed             OrderStatus = "failed"
+)
+
+// TranslationOrder groups translations of one upload into several target
+// languages that are paid for together.
+type TranslationOrder struct {
+	ID               string    `db:"id" json:"id"`
+	UserID           string    `db:"user_id" json:"userId"`
+	SourceLang       string    `db:"source_lang" json:"sourceLang"`
+	ModelKey         string    `db:"model_key" json:"modelKey"`
+	OriginalFilename string    `db:"original_filename" json:"originalFilename"`
+	PriceCents       int64     `db:"price_cents" json:"priceCents"`
+	Currency         string    `db:"currency" json:"currency"`
+	CreatedAt        time.Time `db:"created_at" json:"createdAt"`
+	UpdatedAt        time.Time `db:"updated_at" json:"updatedAt"`
+}
+
+// MemoryEntry is a source segment and its stored translation.
+type MemoryEntry struct {
+	ID            string    `db:"id" json:"id"`
+	UserID        string    `db:"user_id" json:"userId"`
+	SourceLang    string    `db:"source_lang" json:"sourceLang"`
+	TargetLang    string    `db:"target_lang" json:"targetLang"`
+	SourceHash    string    `db:"source_hash" json:"-"`
+	SourceText    string    `db:"source_text" json:"sourceText"`
+	TargetText    string    `db:"target_text" json:"targetText"`
+	TranslationID *string   `db:"translation_id" json:"translationId,omitempty"`
+	CreatedAt     time.Time `db:"created_at" json:"createdAt"`
+	UpdatedAt     time.Time `db:"updated_at" json:"updatedAt"`
+}
+
+// SegmentEdit records a post-edit of one segment of a translation.
+type SegmentEdit struct {
+	ID            string    `db:"id" json:"id"`
+	TranslationID string    `db:"translation_id" json:"translationId"`
+	SegmentIndex  int       `db:"segment_index" json:"segmentIndex"`
+	SourceText    string    `db:"source_text" json:"sourceText"`
+	PreviousText  string    `db:"previous_text" json:"previousText"`
+	TargetText    string    `db:"target_text" json:"targetText"`
+	EditedBy      *string   `db:"edited_by" json:"editedBy,omitempty"`
+	CreatedAt     time.Time `db:"created_at" json:"createdAt"`
 }
 
 // FileRecord stores metadata for files in storage.
@@ -80,6 +154,7 @@ type Payment struct {
 	ID                  string    `db:"id" json:"id"`
 	UserID              string    `db:"user_id" json:"userId"`
 	TranslationID       *string   `db:"translation_id" json:"translationId,omitempty"`
+	OrderID             *string   `db:"order_id" json:"orderId,omitempty"`
 	AmountCents         int64     `db:"amount_cents" json:"amountCents"`
 	Currency            string    `db:"currency" json:"currency"`
 	StripeSessionID     string    `db:"stripe_session_id" json:"stripeSessionId"`
@@ -88,6 +163,19 @@ type Payment struct {
 	CreatedAt           time.Time `db:"created_at" json:"createdAt"`
 }
 
+// Glossary references a terminology list hosted by a translation provider.
+type Glossary struct {
+	ID                 string    `db:"id" json:"id"`
+	UserID             string    `db:"user_id" json:"userId"`
+	Provider           string    `db:"provider" json:"provider"`
+	ProviderGlossaryID string    `db:"provider_glossary_id" json:"-"`
+	Name               string    `db:"name" json:"name"`
+	SourceLang         string    `db:"source_lang" json:"sourceLang"`
+	TargetLang         string    `db:"target_lang" json:"targetLang"`
+	EntryCount         int       `db:"entry_count" json:"entryCount"`
+	CreatedAt          time.Time `db:"created_at" json:"createdAt"`
+}
+
 // LogEntry stores application events for auditing.
 type LogEntry struct {
 	ID        int64     `db:"id" json:"id"`
@@ -110,6 +198,80 @@ type ModelDescriptor struct {
 	MaxCharacters int               `json:"maxCharacters"`
 	SpeedScore    int               `json:"speedScore"`
 	AccuracyScore int               `json:"accuracyScore"`
+	OptionSchema  []OptionSpec      `json:"optionSchema,omitempty"`
+}
+
+// OptionType is the value type of a translation option.
+type OptionType string
+
+const (
+	OptionEnum    OptionType = "enum"
+	OptionInteger OptionType = "integer"
+	OptionBoolean OptionType = "boolean"
+	OptionString  OptionType = "string"
+)
+
+// OptionSpec describes a translation option a model accepts.
+type OptionSpec struct {
+	Key         string      `json:"key"`
+	Type        OptionType  `json:"type"`
+	Description string      `json:"description"`
+	Values      []string    `json:"values,omitempty"`
+	Min         *int        `json:"min,omitempty"`
+	Max         *int        `json:"max,omitempty"`
+	Default     interface{} `json:"default,omitempty"`
+}
+
+// PriceList is a versioned set of model prices valid for a period.
+type PriceList struct {
+	ID              string          `db:"id" json:"id"`
+	Version         int             `db:"version" json:"version"`
+	Name            string          `db:"name" json:"name"`
+	ValidFrom       time.Time       `db:"valid_from" json:"validFrom"`
+	ValidUntil      *time.Time      `db:"valid_until" json:"validUntil,omitempty"`
+	PremiumDiscount float64         `db:"premium_discount" json:"premiumDiscount"`
+	CreatedAt       time.Time       `db:"created_at" json:"createdAt"`
+	Items           []PriceListItem `db:"-" json:"items"`
+}
+
+// PriceListItem is the price of one model in a price list.
+type PriceListItem struct {
+	PriceListID  string  `db:"price_list_id" json:"-"`
+	ModelKey     string  `db:"model_key" json:"modelKey"`
+	PricePer1860 float64 `db:"price_per_1860" json:"pricePer1860"`
+	Currency     string  `db:"currency" json:"currency"`
+}
+
+// ModelRoute names a provider and engine serving a model.
+type ModelRoute struct {
+	Provider string `json:"provider"`
+	Engine   string `json:"engine"`
+}
+
+// CatalogModel is a translation model as stored in the model catalog.
+type CatalogModel struct {
+	Key               string      `db:"key" json:"key"`
+	DisplayName       string      `db:"display_name" json:"displayName"`
+	Tier              string      `db:"tier" json:"tier"`
+	Provider          string      `db:"provider" json:"provider"`
+	Engine            string      `db:"engine" json:"engine"`
+	PricePer1860      float64     `db:"price_per_1860" json:"pricePer1860"`
+	Currency          string      `db:"currency" json:"currency"`
+	Features          StringList  `db:"features" json:"features"`
+	Options           StringMap   `db:"options" json:"options"`
+	MaxCharacters     int         `db:"max_characters" json:"maxCharacters"`
+	SpeedScore        int         `db:"speed_score" json:"speedScore"`
+	AccuracyScore     int         `db:"accuracy_score" json:"accuracyScore"`
+	SupportsFormality bool        `db:"supports_formality" json:"supportsFormality"`
+	SupportsGlossary  bool        `db:"supports_glossary" json:"supportsGlossary"`
+	SupportsDocuments bool        `db:"supports_documents" json:"supportsDocuments"`
+	SupportsImages    bool        `db:"supports_images" json:"supportsImages"`
+	SupportsXMLTags   bool        `db:"supports_xml_tags" json:"supportsXmlTags"`
+	Fallbacks         ModelRoutes `db:"fallbacks" json:"fallbacks"`
+	Enabled           bool        `db:"enabled" json:"enabled"`
+	SortOrder         int         `db:"sort_order" json:"sortOrder"`
+	CreatedAt         time.Time   `db:"created_at" json:"createdAt"`
+	UpdatedAt         time.Time   `db:"updated_at" json:"updatedAt"`
 }
 
 // InvoiceMetadata holds data needed to render PDF invoices.
@@ -118,6 +280,9 @@ type InvoiceMetadata struct {
 	User             User
 	Translation      Translation
 	Model            ModelDescriptor
+	PriceListVersion *int
+	UnitPrice        float64
+	Discount         float64
 	NetAmountCents   int64
 	TaxAmountCents   int64
 	GrossAmountCents int64
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/stripe/stripe-go/v75"
 	"github.com/stripe/stripe-go/v75/client"
@@ -29,6 +30,18 @@ type TranslationSessionParams struct {
 	CancelURL   string
 	Metadata    map[string]string
 	CustomerID  string
+	// Items itemises the amount, e.g. per target language. A single line
+	// over AmountCents is used when empty.
+	Items []LineItem
+	// Currency is the currency the amount was quoted in. The client's
+	// default currency is used when empty.
+	Currency string
+}
+
+// LineItem is one position of a checkout session.
+type LineItem struct {
+	Name        string
+	AmountCents int64
 }
 
 // CreateTranslationCheckoutSession starts a one-off payment session.
@@ -36,20 +49,30 @@ func (c *StripeClient) CreateTranslationCheckoutSession(ctx context.Context, par
 	if params.AmountCents <= 0 {
 		return nil, errors.New("amount must be positive")
 	}
-	sessionParams := &stripe.CheckoutSessionParams{
-		Mode: stripe.String(string(stripe.CheckoutSessionModePayment)),
-		LineItems: []*stripe.CheckoutSessionLineItemParams{
-			{
-				PriceData: &stripe.CheckoutSessionLineItemPriceDataParams{
-					Currency: stripe.String(c.currency),
-					ProductData: &stripe.CheckoutSessionLineItemPriceDataProductDataParams{
-						Name: stripe.String("Kaminskyi Übersetzungsdienst"),
-					},
-					UnitAmount: stripe.Int64(params.AmountCents),
+	items := params.Items
+	if len(items) == 0 {
+		items = []LineItem{{Name: "Kaminskyi Übersetzungsdienst", AmountCents: params.AmountCents}}
+	}
+	currency := strings.ToLower(strings.TrimSpace(params.Currency))
+	if currency == "" {
+		currency = c.currency
+	}
+	lineItems := make([]*stripe.CheckoutSessionLineItemParams, 0, len(items))
+	for _, item := range items {
+		lineItems = append(lineItems, &stripe.CheckoutSessionLineItemParams{
+			PriceData: &stripe.CheckoutSessionLineItemPriceDataParams{
+				Currency: stripe.String(currency),
+				ProductData: &stripe.CheckoutSessionLineItemPriceDataProductDataParams{
+					Name: stripe.String(item.Name),
 				},
-				Quantity: stripe.Int64(1),
+				UnitAmount: stripe.Int64(item.AmountCents),
 			},
-		},
+			Quantity: stripe.Int64(1),
+		})
+	}
+	sessionParams := &stripe.CheckoutSessionParams{
+		Mode:       stripe.String(string(stripe.CheckoutSessionModePayment)),
+		LineItems:  lineItems,
 		SuccessURL: stripe.String(params.SuccessURL),
 		CancelURL:  stripe.String(params.CancelURL),
 	}
@@ -37,6 +37,19 @@ func (c *Client) EnqueueTranslation(payload TranslatePayload) (*asynq.TaskInfo,
 	return c.client.Enqueue(task, asynq.Queue("translations"))
 }
 
+// EnqueueTranslationIn schedules a translation job after delay.
+func (c *Client) EnqueueTranslationIn(payload TranslatePayload, delay time.Duration) (*asynq.TaskInfo, error) {
+	if delay <= 0 {
+		return c.EnqueueTranslation(payload)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	task := asynq.NewTask(TaskTranslateDocument, body, asynq.MaxRetry(3), asynq.Timeout(30*time.Minute))
+	return c.client.Enqueue(task, asynq.Queue("translations"), asynq.ProcessIn(delay))
+}
+
 // EnqueueCleanup schedules file deletion.
 func (c *Client) EnqueueCleanup(payload CleanupPayload, delay time.Duration) (*asynq.TaskInfo, error) {
 	body, err := json.Marshal(payload)
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/models"
+)
+
+// GlossaryRepository persists glossary references.
+type GlossaryRepository struct {
+	db *sqlx.DB
+}
+
+// NewGlossaryRepository constructs GlossaryRepository.
+func NewGlossaryRepository(db *sqlx.DB) *GlossaryRepository {
+	return &GlossaryRepository{db: db}
+}
+
+// Create inserts a glossary row.
+func (r *GlossaryRepository) Create(ctx context.Context, glossary *models.Glossary) (*models.Glossary, error) {
+	glossary.ID = uuid.NewString()
+	glossary.CreatedAt = time.Now().UTC()
+	query := `INSERT INTO glossaries (id, user_id, provider, provider_glossary_id, name, source_lang, target_lang, entry_count, created_at)
+              VALUES (:id, :user_id, :provider, :provider_glossary_id, :name, :source_lang, :target_lang, :entry_count, :created_at)`
+	if _, err := r.db.NamedExecContext(ctx, query, glossary); err != nil {
+		return nil, err
+	}
+	return glossary, nil
+}
+
+// GetByID fetches a glossary by ID.
+func (r *GlossaryRepository) GetByID(ctx context.Context, id string) (*models.Glossary, error) {
+	var glossary models.Glossary
+	if err := r.db.GetContext(ctx, &glossary, `SELECT * FROM glossaries WHERE id=$1`, id); err != nil {
+		return nil, err
+	}
+	return &glossary, nil
+}
+
+// ListByUser fetches glossaries owned by a user.
+func (r *GlossaryRepository) ListByUser(ctx context.Context, userID string) ([]models.Glossary, error) {
+	glossaries := []models.Glossary{}
+	if err := r.db.SelectContext(ctx, &glossaries, `SELECT * FROM glossaries WHERE user_id=$1 ORDER BY created_at DESC`, userID); err != nil {
+		return nil, err
+	}
+	return glossaries, nil
+}
+
+// Delete removes a glossary row.
+func (r *GlossaryRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM glossaries WHERE id=$1`, id)
+	return err
+}
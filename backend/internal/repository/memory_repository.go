@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/models"
+)
+
+// MemoryRepository persists translation memory segments.
+type MemoryRepository struct {
+	db *sqlx.DB
+}
+
+// NewMemoryRepository constructs MemoryRepository.
+func NewMemoryRepository(db *sqlx.DB) *MemoryRepository {
+	return &MemoryRepository{db: db}
+}
+
+// Lookup fetches the entries of a language pair matching the source hashes.
+func (r *MemoryRepository) Lookup(ctx context.Context, userID, sourceLang, targetLang string, hashes []string) ([]models.MemoryEntry, error) {
+	entries := []models.MemoryEntry{}
+	if len(hashes) == 0 {
+		return entries, nil
+	}
+	query, args, err := sqlx.In(`SELECT * FROM translation_memory WHERE user_id=? AND source_lang=? AND target_lang=? AND source_hash IN (?)`, userID, sourceLang, targetLang, hashes)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.db.SelectContext(ctx, &entries, r.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Candidates fetches the most recent source segments of a language pair
+// whose length lies within [minChars, maxChars], for fuzzy matching.
+func (r *MemoryRepository) Candidates(ctx context.Context, userID, sourceLang, targetLang string, minChars, maxChars, limit int) ([]string, error) {
+	sources := []string{}
+	query := `SELECT source_text FROM translation_memory
+              WHERE user_id=$1 AND source_lang=$2 AND target_lang=$3 AND char_length(source_text) BETWEEN $4 AND $5
+              ORDER BY updated_at DESC LIMIT $6`
+	if err := r.db.SelectContext(ctx, &sources, query, userID, sourceLang, targetLang, minChars, maxChars, limit); err != nil {
+		return nil, err
+	}
+	return sources, nil
+}
+
+// Upsert stores entries, replacing the target of segments already known.
+func (r *MemoryRepository) Upsert(ctx context.Context, entries []models.MemoryEntry) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	now := time.Now().UTC()
+	query := `INSERT INTO translation_memory (id, user_id, source_lang, target_lang, source_hash, source_text, target_text, translation_id, created_at, updated_at)
+              VALUES (:id, :user_id, :source_lang, :target_lang, :source_hash, :source_text, :target_text, :translation_id, :created_at, :updated_at)
+              ON CONFLICT (user_id, source_lang, target_lang, source_hash)
+              DO UPDATE SET target_text=EXCLUDED.target_text, translation_id=EXCLUDED.translation_id, updated_at=EXCLUDED.updated_at`
+	for i := range entries {
+		entries[i].ID = uuid.NewString()
+		entries[i].CreatedAt = now
+		entries[i].UpdatedAt = now
+		if _, err := tx.NamedExecContext(ctx, query, &entries[i]); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/models"
+)
+
+// ModelRepository persists the translation model catalog.
+type ModelRepository struct {
+	db *sqlx.DB
+}
+
+// NewModelRepository constructs ModelRepository.
+func NewModelRepository(db *sqlx.DB) *ModelRepository {
+	return &ModelRepository{db: db}
+}
+
+const modelColumns = `key, display_name, tier, provider, engine, price_per_1860, currency, features, options, max_characters, speed_score, accuracy_score,
+              supports_formality, supports_glossary, supports_documents, supports_images, supports_xml_tags, fallbacks, enabled, sort_order, created_at, updated_at`
+
+const modelValues = `:key, :display_name, :tier, :provider, :engine, :price_per_1860, :currency, :features, :options, :max_characters, :speed_score, :accuracy_score,
+              :supports_formality, :supports_glossary, :supports_documents, :supports_images, :supports_xml_tags, :fallbacks, :enabled, :sort_order, :created_at, :updated_at`
+
+// List fetches every model in catalog order.
+func (r *ModelRepository) List(ctx context.Context) ([]models.CatalogModel, error) {
+	catalog := []models.CatalogModel{}
+	if err := r.db.SelectContext(ctx, &catalog, `SELECT * FROM translation_models ORDER BY sort_order, key`); err != nil {
+		return nil, err
+	}
+	return catalog, nil
+}
+
+// Create inserts a model.
+func (r *ModelRepository) Create(ctx context.Context, model *models.CatalogModel) (*models.CatalogModel, error) {
+	now := time.Now().UTC()
+	model.CreatedAt = now
+	model.UpdatedAt = now
+	query := `INSERT INTO translation_models (` + modelColumns + `) VALUES (` + modelValues + `)`
+	if _, err := r.db.NamedExecContext(ctx, query, model); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+// Seed inserts models whose key is not stored yet and keeps stored ones.
+func (r *ModelRepository) Seed(ctx context.Context, catalog []models.CatalogModel) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	now := time.Now().UTC()
+	query := `INSERT INTO translation_models (` + modelColumns + `) VALUES (` + modelValues + `) ON CONFLICT (key) DO NOTHING`
+	for i := range catalog {
+		catalog[i].CreatedAt = now
+		catalog[i].UpdatedAt = now
+		if _, err := tx.NamedExecContext(ctx, query, &catalog[i]); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Update replaces a model; it returns sql.ErrNoRows for unknown keys.
+func (r *ModelRepository) Update(ctx context.Context, model *models.CatalogModel) (*models.CatalogModel, error) {
+	model.UpdatedAt = time.Now().UTC()
+	query := `UPDATE translation_models SET display_name=:display_name, tier=:tier, provider=:provider, engine=:engine, price_per_1860=:price_per_1860,
+              currency=:currency, features=:features, options=:options, max_characters=:max_characters, speed_score=:speed_score, accuracy_score=:accuracy_score,
+              supports_formality=:supports_formality, supports_glossary=:supports_glossary, supports_documents=:supports_documents, supports_images=:supports_images,
+              supports_xml_tags=:supports_xml_tags, fallbacks=:fallbacks, enabled=:enabled, sort_order=:sort_order, updated_at=:updated_at
+              WHERE key=:key`
+	res, err := r.db.NamedExecContext(ctx, query, model)
+	if err != nil {
+		return nil, err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return model, nil
+}
+
+// InUse reports whether translations or orders reference the model.
+func (r *ModelRepository) InUse(ctx context.Context, key string) (bool, error) {
+	var inUse bool
+	err := r.db.GetContext(ctx, &inUse, `SELECT EXISTS (SELECT 1 FROM translations WHERE model_key=$1)
+              OR EXISTS (SELECT 1 FROM translation_orders WHERE model_key=$1)`, key)
+	return inUse, err
+}
+
+// Delete removes a model; it returns sql.ErrNoRows for unknown keys.
+func (r *ModelRepository) Delete(ctx context.Context, key string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM translation_models WHERE key=$1`, key)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
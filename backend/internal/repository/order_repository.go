@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/models"
+)
+
+// OrderRepository persists multi-language translation orders.
+type OrderRepository struct {
+	db *sqlx.DB
+}
+
+// NewOrderRepository constructs OrderRepository.
+func NewOrderRepository(db *sqlx.DB) *OrderRepository {
+	return &OrderRepository{db: db}
+}
+
+// Create inserts an order row.
+func (r *OrderRepository) Create(ctx context.Context, order *models.TranslationOrder) (*models.TranslationOrder, error) {
+	if order.ID == "" {
+		order.ID = uuid.NewString()
+	}
+	now := time.Now().UTC()
+	order.CreatedAt = now
+	order.UpdatedAt = now
+	query := `INSERT INTO translation_orders (id, user_id, source_lang, model_key, original_filename, price_cents, currency, created_at, updated_at)
+              VALUES (:id, :user_id, :source_lang, :model_key, :original_filename, :price_cents, :currency, :created_at, :updated_at)`
+	if _, err := r.db.NamedExecContext(ctx, query, order); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// GetByID fetches an order by ID.
+func (r *OrderRepository) GetByID(ctx context.Context, id string) (*models.TranslationOrder, error) {
+	var order models.TranslationOrder
+	if err := r.db.GetContext(ctx, &order, `SELECT * FROM translation_orders WHERE id=$1`, id); err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// ListByUser fetches orders for a specific user.
+func (r *OrderRepository) ListByUser(ctx context.Context, userID string, limit, offset int) ([]models.TranslationOrder, error) {
+	orders := []models.TranslationOrder{}
+	query := `SELECT * FROM translation_orders WHERE user_id=$1 ORDER BY created_at DESC LIMIT $2 OFFSET $3`
+	if err := r.db.SelectContext(ctx, &orders, query, userID, limit, offset); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// Delete removes an order together with its translations and their files.
+func (r *OrderRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM translation_orders WHERE id=$1`, id)
+	return err
+}
@@ -23,8 +23,8 @@ func NewPaymentRepository(db *sqlx.DB) *PaymentRepository {
 func (r *PaymentRepository) Create(ctx context.Context, payment *models.Payment) (*models.Payment, error) {
 	payment.ID = uuid.NewString()
 	payment.CreatedAt = time.Now().UTC()
-	query := `INSERT INTO payments (id, user_id, translation_id, amount_cents, currency, stripe_session_id, stripe_payment_intent, status, created_at)
-              VALUES (:id, :user_id, :translation_id, :amount_cents, :currency, :stripe_session_id, :stripe_payment_intent, :status, :created_at)`
+	query := `INSERT INTO payments (id, user_id, translation_id, order_id, amount_cents, currency, stripe_session_id, stripe_payment_intent, status, created_at)
+              VALUES (:id, :user_id, :translation_id, :order_id, :amount_cents, :currency, :stripe_session_id, :stripe_payment_intent, :status, :created_at)`
 	if _, err := r.db.NamedExecContext(ctx, query, payment); err != nil {
 		return nil, err
 	}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/models"
+)
+
+// PriceListRepository persists versioned price lists.
+type PriceListRepository struct {
+	db *sqlx.DB
+}
+
+// NewPriceListRepository constructs PriceListRepository.
+func NewPriceListRepository(db *sqlx.DB) *PriceListRepository {
+	return &PriceListRepository{db: db}
+}
+
+// Create stores a price list with its items under the next version number.
+func (r *PriceListRepository) Create(ctx context.Context, list *models.PriceList) (*models.PriceList, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	// Serialises version numbering between concurrent writers.
+	if _, err := tx.ExecContext(ctx, `LOCK TABLE price_lists IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+		return nil, err
+	}
+	if err := tx.GetContext(ctx, &list.Version, `SELECT COALESCE(MAX(version), 0) + 1 FROM price_lists`); err != nil {
+		return nil, err
+	}
+	list.ID = uuid.NewString()
+	list.CreatedAt = time.Now().UTC()
+	query := `INSERT INTO price_lists (id, version, name, valid_from, valid_until, premium_discount, created_at)
+              VALUES (:id, :version, :name, :valid_from, :valid_until, :premium_discount, :created_at)`
+	if _, err := tx.NamedExecContext(ctx, query, list); err != nil {
+		return nil, err
+	}
+	itemQuery := `INSERT INTO price_list_items (price_list_id, model_key, price_per_1860, currency)
+                  VALUES (:price_list_id, :model_key, :price_per_1860, :currency)`
+	for i := range list.Items {
+		list.Items[i].PriceListID = list.ID
+		if _, err := tx.NamedExecContext(ctx, itemQuery, &list.Items[i]); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// List fetches every price list with its items, newest version first.
+func (r *PriceListRepository) List(ctx context.Context) ([]models.PriceList, error) {
+	lists := []models.PriceList{}
+	if err := r.db.SelectContext(ctx, &lists, `SELECT * FROM price_lists ORDER BY version DESC`); err != nil {
+		return nil, err
+	}
+	for i := range lists {
+		if err := r.loadItems(ctx, &lists[i]); err != nil {
+			return nil, err
+		}
+	}
+	return lists, nil
+}
+
+// Active returns the highest version valid at the given time, or nil.
+func (r *PriceListRepository) Active(ctx context.Context, at time.Time) (*models.PriceList, error) {
+	var list models.PriceList
+	query := `SELECT * FROM price_lists WHERE valid_from <= $1 AND (valid_until IS NULL OR valid_until > $1)
+              ORDER BY version DESC LIMIT 1`
+	if err := r.db.GetContext(ctx, &list, query, at); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if err := r.loadItems(ctx, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+func (r *PriceListRepository) loadItems(ctx context.Context, list *models.PriceList) error {
+	list.Items = []models.PriceListItem{}
+	return r.db.SelectContext(ctx, &list.Items, `SELECT * FROM price_list_items WHERE price_list_id=$1 ORDER BY model_key`, list.ID)
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/models"
+)
+
+// SegmentEditRepository persists post-edits of translation segments.
+type SegmentEditRepository struct {
+	db *sqlx.DB
+}
+
+// NewSegmentEditRepository constructs SegmentEditRepository.
+func NewSegmentEditRepository(db *sqlx.DB) *SegmentEditRepository {
+	return &SegmentEditRepository{db: db}
+}
+
+// Create stores edits in one transaction.
+func (r *SegmentEditRepository) Create(ctx context.Context, edits []models.SegmentEdit) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	now := time.Now().UTC()
+	query := `INSERT INTO translation_segment_edits (id, translation_id, segment_index, source_text, previous_text, target_text, edited_by, created_at)
+              VALUES (:id, :translation_id, :segment_index, :source_text, :previous_text, :target_text, :edited_by, :created_at)`
+	for i := range edits {
+		edits[i].ID = uuid.NewString()
+		edits[i].CreatedAt = now
+		if _, err := tx.NamedExecContext(ctx, query, &edits[i]); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// ListByTranslation fetches the edits of a translation, oldest first.
+func (r *SegmentEditRepository) ListByTranslation(ctx context.Context, translationID string) ([]models.SegmentEdit, error) {
+	edits := []models.SegmentEdit{}
+	query := `SELECT * FROM translation_segment_edits WHERE translation_id=$1 ORDER BY created_at, segment_index`
+	if err := r.db.SelectContext(ctx, &edits, query, translationID); err != nil {
+		return nil, err
+	}
+	return edits, nil
+}
@@ -29,8 +29,8 @@ func (r *TranslationRepository) Create(ctx context.Context, translation *models.
 	translation.UpdatedAt = now
 	translation.Status = models.TranslationPending
 
-	query := `INSERT INTO translations (id, user_id, source_lang, target_lang, model_key, character_count, price_cents, currency, options, status, queue_task_id, original_filename, delete_after, created_at, updated_at)
-              VALUES (:id, :user_id, :source_lang, :target_lang, :model_key, :character_count, :price_cents, :currency, :options, :status, :queue_task_id, :original_filename, :delete_after, :created_at, :updated_at)`
+	query := `INSERT INTO translations (id, user_id, order_id, source_lang, detected_source_lang, detection_confidence, target_lang, model_key, character_count, tm_matched_characters, price_cents, currency, price_list_version, unit_price, price_discount, options, status, queue_task_id, original_filename, delete_after, created_at, updated_at)
+              VALUES (:id, :user_id, :order_id, :source_lang, :detected_source_lang, :detection_confidence, :target_lang, :model_key, :character_count, :tm_matched_characters, :price_cents, :currency, :price_list_version, :unit_price, :price_discount, :options, :status, :queue_task_id, :original_filename, :delete_after, :created_at, :updated_at)`
 
 	if _, err := r.db.NamedExecContext(ctx, query, translation); err != nil {
 		return nil, err
@@ -51,6 +51,19 @@ func (r *TranslationRepository) MarkCompleted(ctx context.Context, id string, tr
 	return err
 }
 
+// SetProcessedBy records which provider and engine produced the output.
+func (r *TranslationRepository) SetProcessedBy(ctx context.Context, id string, provider, engine string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE translations SET processed_provider=$1, processed_engine=$2, updated_at=$3 WHERE id=$4`, provider, engine, time.Now().UTC(), id)
+	return err
+}
+
+// SetProviderJob stores the handle of the remote job processing the
+// translation so a retried task can resume it.
+func (r *TranslationRepository) SetProviderJob(ctx context.Context, id string, provider, engine, jobID, jobKey string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE translations SET provider_job_provider=$1, provider_job_engine=$2, provider_job_id=$3, provider_job_key=$4, updated_at=$5 WHERE id=$6`, provider, engine, jobID, jobKey, time.Now().UTC(), id)
+	return err
+}
+
 // SetQueueTaskID stores queue task identifier.
 func (r *TranslationRepository) SetQueueTaskID(ctx context.Context, id string, taskID string) error {
 	_, err := r.db.ExecContext(ctx, `UPDATE translations SET queue_task_id=$1, status=$2, updated_at=$3 WHERE id=$4`, taskID, models.TranslationQueued, time.Now().UTC(), id)
@@ -76,6 +89,26 @@ func (r *TranslationRepository) ListByUser(ctx context.Context, userID string, l
 	return translations, nil
 }
 
+// ListCompletedByUser fetches the completed translations of a user, oldest
+// first.
+func (r *TranslationRepository) ListCompletedByUser(ctx context.Context, userID string) ([]models.Translation, error) {
+	translations := []models.Translation{}
+	query := `SELECT * FROM translations WHERE user_id=$1 AND status=$2 ORDER BY created_at`
+	if err := r.db.SelectContext(ctx, &translations, query, userID, models.TranslationCompleted); err != nil {
+		return nil, err
+	}
+	return translations, nil
+}
+
+// ListByOrder fetches the translations of an order.
+func (r *TranslationRepository) ListByOrder(ctx context.Context, orderID string) ([]models.Translation, error) {
+	translations := []models.Translation{}
+	if err := r.db.SelectContext(ctx, &translations, `SELECT * FROM translations WHERE order_id=$1 ORDER BY target_lang`, orderID); err != nil {
+		return nil, err
+	}
+	return translations, nil
+}
+
 // PendingForDeletion returns translations whose files should be deleted.
 func (r *TranslationRepository) PendingForDeletion(ctx context.Context, cutoff time.Time) ([]models.Translation, error) {
 	translations := []models.Translation{}
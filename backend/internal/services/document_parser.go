@@ -10,6 +10,8 @@ import (
 	"strings"
 
 	pdf "github.com/ledongthuc/pdf"
+
+	"github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/translation"
 )
 
 var xmlTagRegex = regexp.MustCompile(`<[^>]+>`)
@@ -26,6 +28,21 @@ func ExtractText(filename string, data []byte) (string, error) {
 		return extractFromDocx(data)
 	case ".epub":
 		return extractFromEpub(data)
+	case ".html", ".htm", ".xhtml":
+		segments, err := translation.DocumentSegments(filename, data)
+		if err != nil {
+			return "", err
+		}
+		return xmlTagRegex.ReplaceAllString(strings.Join(segments, "\n"), ""), nil
+	case ".md", ".markdown", ".json", ".po", ".pot", ".xml", ".strings", ".xcstrings", ".yaml", ".yml":
+		segments, err := translation.DocumentSegments(filename, data)
+		if err != nil {
+			return "", err
+		}
+		return strings.Join(segments, "\n"), nil
+	case ".srt", ".vtt":
+		// Only the spoken text is counted, not timecodes or numbering.
+		return translation.SubtitleText(data), nil
 	default:
 		return "", errors.New("unsupported file type")
 	}
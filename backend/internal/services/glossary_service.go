@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog"
+
+	"github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/models"
+	"github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/repository"
+	"github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/translation"
+)
+
+// ErrGlossaryNotFound is returned for unknown or foreign glossaries.
+var ErrGlossaryNotFound = errors.New("glossary not found")
+
+// ErrGlossaryProvider is returned when the glossary provider is unavailable
+// or fails for reasons other than rejecting the glossary.
+var ErrGlossaryProvider = errors.New("glossary provider failed")
+
+// GlossaryService manages user glossaries hosted by DeepL.
+type GlossaryService struct {
+	glossaries *repository.GlossaryRepository
+	deepl      *translation.DeepLClient
+	logger     zerolog.Logger
+}
+
+// NewGlossaryService constructs GlossaryService.
+func NewGlossaryService(glossaries *repository.GlossaryRepository, deepl *translation.DeepLClient, logger zerolog.Logger) *GlossaryService {
+	return &GlossaryService{glossaries: glossaries, deepl: deepl, logger: logger}
+}
+
+// CreateGlossaryInput holds glossary creation values.
+type CreateGlossaryInput struct {
+	UserID        string
+	Name          string
+	SourceLang    string
+	TargetLang    string
+	Entries       string
+	EntriesFormat string
+}
+
+// GlossaryDetails combines stored metadata with provider entries.
+type GlossaryDetails struct {
+	models.Glossary
+	Ready   bool                        `json:"ready"`
+	Entries []translation.GlossaryEntry `json:"entries"`
+}
+
+// Create validates entries, uploads them to DeepL and stores the reference.
+func (s *GlossaryService) Create(ctx context.Context, input CreateGlossaryInput) (*models.Glossary, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, errors.New("glossary name required")
+	}
+	sourceLang := translation.BaseLanguage(input.SourceLang)
+	targetLang := translation.BaseLanguage(input.TargetLang)
+	if sourceLang == "" || targetLang == "" {
+		return nil, errors.New("source and target language required")
+	}
+	if sourceLang == targetLang {
+		return nil, errors.New("source and target language must differ")
+	}
+	entries, err := translation.ParseGlossaryEntries(input.Entries, input.EntriesFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := s.deepl.CreateGlossary(ctx, name, sourceLang, targetLang, entries)
+	if errors.Is(err, translation.ErrInvalidRequest) {
+		return nil, fmt.Errorf("create glossary: %w", err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: create glossary: %v", ErrGlossaryProvider, err)
+	}
+	glossary, err := s.glossaries.Create(ctx, &models.Glossary{
+		UserID:             input.UserID,
+		Provider:           string(translation.ProviderDeepL),
+		ProviderGlossaryID: info.GlossaryID,
+		Name:               name,
+		SourceLang:         sourceLang,
+		TargetLang:         targetLang,
+		EntryCount:         len(entries),
+	})
+	if err != nil {
+		if derr := s.deepl.DeleteGlossary(ctx, info.GlossaryID); derr != nil {
+			s.logger.Warn().Err(derr).Str("glossary_id", info.GlossaryID).Msg("failed to roll back deepl glossary")
+		}
+		return nil, err
+	}
+	s.logger.Info().Str("glossary_id", glossary.ID).Int("entries", len(entries)).Msg("glossary created")
+	return glossary, nil
+}
+
+// List returns glossaries owned by the user.
+func (s *GlossaryService) List(ctx context.Context, userID string) ([]models.Glossary, error) {
+	return s.glossaries.ListByUser(ctx, userID)
+}
+
+// Get returns an owned glossary. Unknown and foreign glossaries are reported
+// as ErrGlossaryNotFound; other repository errors are returned unchanged.
+func (s *GlossaryService) Get(ctx context.Context, userID, id string) (*models.Glossary, error) {
+	glossary, err := s.glossaries.GetByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrGlossaryNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load glossary: %w", err)
+	}
+	if glossary.UserID != userID {
+		return nil, ErrGlossaryNotFound
+	}
+	return glossary, nil
+}
+
+// Details returns an owned glossary together with its entries.
+func (s *GlossaryService) Details(ctx context.Context, userID, id string) (*GlossaryDetails, error) {
+	glossary, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	info, err := s.deepl.GetGlossary(ctx, glossary.ProviderGlossaryID)
+	if err != nil {
+		return nil, fmt.Errorf("load glossary: %w", err)
+	}
+	entries, err := s.deepl.GlossaryEntries(ctx, glossary.ProviderGlossaryID)
+	if err != nil {
+		return nil, fmt.Errorf("load glossary entries: %w", err)
+	}
+	return &GlossaryDetails{Glossary: *glossary, Ready: info.Ready, Entries: entries}, nil
+}
+
+// Delete removes an owned glossary from DeepL and the database.
+func (s *GlossaryService) Delete(ctx context.Context, userID, id string) error {
+	glossary, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.deepl.DeleteGlossary(ctx, glossary.ProviderGlossaryID); err != nil {
+		return fmt.Errorf("delete glossary: %w", err)
+	}
+	return s.glossaries.Delete(ctx, glossary.ID)
+}
+
+// ResolveForTranslation checks that an owned glossary fits the model and
+// language pair and returns it.
+func (s *GlossaryService) ResolveForTranslation(ctx context.Context, userID, id string, model translation.Model, sourceLang, targetLang string) (*models.Glossary, error) {
+	glossary, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !model.SupportsGlossary || string(model.Provider) != glossary.Provider {
+		return nil, fmt.Errorf("model %s does not support this glossary", model.Key)
+	}
+	if sourceLang != "" && translation.BaseLanguage(sourceLang) != glossary.SourceLang {
+		return nil, fmt.Errorf("glossary source language %s does not match %s", glossary.SourceLang, sourceLang)
+	}
+	if translation.BaseLanguage(targetLang) != glossary.TargetLang {
+		return nil, fmt.Errorf("glossary target language %s does not match %s", glossary.TargetLang, targetLang)
+	}
+	return glossary, nil
+}
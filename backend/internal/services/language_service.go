@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/translation"
+)
+
+// ErrUnsupportedLanguage is returned for language codes a model cannot serve.
+var ErrUnsupportedLanguage = errors.New("unsupported language")
+
+// JSONCache stores JSON values with a TTL, e.g. in Redis.
+type JSONCache interface {
+	SetJSON(key string, value interface{}, ttl time.Duration) error
+	GetJSON(key string, dest interface{}) (bool, error)
+}
+
+// ModelLanguages lists the languages one model accepts.
+type ModelLanguages struct {
+	Model    string                   `json:"model"`
+	Provider translation.ProviderType `json:"provider"`
+	translation.LanguageSet
+}
+
+// LanguageService resolves and caches provider language lists.
+type LanguageService struct {
+	providers *translation.Registry
+	cache     JSONCache
+	ttl       time.Duration
+	logger    zerolog.Logger
+}
+
+// NewLanguageService constructs LanguageService. cache may be nil.
+func NewLanguageService(providers *translation.Registry, cache JSONCache, ttl time.Duration, logger zerolog.Logger) *LanguageService {
+	return &LanguageService{providers: providers, cache: cache, ttl: ttl, logger: logger}
+}
+
+// List returns the languages of every catalog model, or of modelKey only.
+func (s *LanguageService) List(ctx context.Context, modelKey string) ([]ModelLanguages, error) {
+	var catalog []translation.Model
+	for _, model := range translation.Models() {
+		if !model.Disabled {
+			catalog = append(catalog, model)
+		}
+	}
+	if modelKey != "" {
+		model := translation.GetModelByKey(modelKey)
+		if model == nil {
+			return nil, fmt.Errorf("unknown model %s", modelKey)
+		}
+		catalog = []translation.Model{*model}
+	}
+	result := make([]ModelLanguages, 0, len(catalog))
+	for _, model := range catalog {
+		set, err := s.ForModel(ctx, model)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, ModelLanguages{Model: model.Key, Provider: model.Provider, LanguageSet: *set})
+	}
+	return result, nil
+}
+
+// ForModel returns the languages of the model's primary provider.
+func (s *LanguageService) ForModel(ctx context.Context, model translation.Model) (*translation.LanguageSet, error) {
+	key := "languages:" + string(model.Provider)
+	if s.cache != nil {
+		var cached translation.LanguageSet
+		found, err := s.cache.GetJSON(key, &cached)
+		if err != nil {
+			s.logger.Warn().Err(err).Str("key", key).Msg("failed to read language cache")
+		} else if found {
+			return &cached, nil
+		}
+	}
+
+	provider, err := s.providers.ForModel(model)
+	if err != nil {
+		return nil, err
+	}
+	source, ok := provider.(translation.LanguageSource)
+	if !ok {
+		set := translation.StaticLanguages
+		return &set, nil
+	}
+	set, err := source.Languages(ctx)
+	if err != nil {
+		// Keep validating against the static list while the provider is
+		// unreachable instead of rejecting every order.
+		s.logger.Warn().Err(err).Str("provider", string(model.Provider)).Msg("failed to fetch provider languages")
+		fallback := translation.StaticLanguages
+		return &fallback, nil
+	}
+	if s.cache != nil {
+		if err := s.cache.SetJSON(key, set, s.ttl); err != nil {
+			s.logger.Warn().Err(err).Str("key", key).Msg("failed to write language cache")
+		}
+	}
+	return set, nil
+}
+
+// SupportsSource reports whether the model translates from sourceLang.
+// Unknown language lists are treated as supporting it.
+func (s *LanguageService) SupportsSource(ctx context.Context, model translation.Model, sourceLang string) bool {
+	if s == nil {
+		return true
+	}
+	set, err := s.ForModel(ctx, model)
+	if err != nil {
+		return true
+	}
+	return set.SupportsSource(sourceLang)
+}
+
+// TargetLanguage returns the model's target language matching code. Without
+// a language service every target is taken to support formality.
+func (s *LanguageService) TargetLanguage(ctx context.Context, model translation.Model, code string) (translation.Language, bool) {
+	if s == nil {
+		return translation.Language{Code: code, SupportsFormality: true}, true
+	}
+	set, err := s.ForModel(ctx, model)
+	if err != nil {
+		return translation.Language{Code: code}, false
+	}
+	language, ok := set.TargetLanguage(code)
+	if !ok {
+		language.Code = code
+	}
+	return language, ok
+}
+
+// Validate checks the language pair against the model. An empty source
+// language means auto-detection.
+func (s *LanguageService) Validate(ctx context.Context, model translation.Model, sourceLang, targetLang string) error {
+	if s == nil {
+		return nil
+	}
+	set, err := s.ForModel(ctx, model)
+	if err != nil {
+		return err
+	}
+	if sourceLang != "" && !set.SupportsSource(sourceLang) {
+		return fmt.Errorf("%w: source language %s is not supported by %s", ErrUnsupportedLanguage, sourceLang, model.Key)
+	}
+	if _, ok := set.TargetLanguage(targetLang); !ok {
+		return fmt.Errorf("%w: target language %s is not supported by %s", ErrUnsupportedLanguage, targetLang, model.Key)
+	}
+	return nil
+}
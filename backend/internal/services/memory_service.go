@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rs/zerolog"
+
+	"github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/models"
+	"github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/repository"
+	"github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/translation"
+)
+
+// MemoryService reuses translated segments across a user's translations.
+type MemoryService struct {
+	memory        *repository.MemoryRepository
+	weights       translation.MatchWeights
+	maxCandidates int
+	logger        zerolog.Logger
+}
+
+// NewMemoryService constructs MemoryService. weights are the shares of the
+// regular price charged per match band; maxCandidates bounds the memory
+// segments compared for fuzzy matches.
+func NewMemoryService(memory *repository.MemoryRepository, weights translation.MatchWeights, maxCandidates int, logger zerolog.Logger) *MemoryService {
+	return &MemoryService{memory: memory, weights: weights, maxCandidates: maxCandidates, logger: logger}
+}
+
+// Lookup returns stored translations keyed by normalized source segment.
+func (s *MemoryService) Lookup(ctx context.Context, userID, sourceLang, targetLang string, segments []string) (map[string]string, error) {
+	result := make(map[string]string)
+	if s == nil || sourceLang == "" || len(segments) == 0 {
+		return result, nil
+	}
+	seen := make(map[string]bool, len(segments))
+	hashes := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		hash := translation.SegmentHash(segment)
+		if !seen[hash] {
+			seen[hash] = true
+			hashes = append(hashes, hash)
+		}
+	}
+	entries, err := s.memory.Lookup(ctx, userID, memorySourceLang(sourceLang), memoryTargetLang(targetLang), hashes)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		result[translation.NormalizeSegment(entry.SourceText)] = entry.TargetText
+	}
+	return result, nil
+}
+
+// Analyze classifies the segments of a document against the memory:
+// repetitions, exact and fuzzy matches, and new text.
+func (s *MemoryService) Analyze(ctx context.Context, userID, sourceLang, targetLang string, segments []string) (*translation.Analysis, error) {
+	if s == nil {
+		analysis := translation.AnalyzeSegments(segments, nil, nil, nil)
+		return &analysis, nil
+	}
+	exact, err := s.Lookup(ctx, userID, sourceLang, targetLang, segments)
+	if err != nil {
+		return nil, err
+	}
+	var candidates []string
+	if sourceLang != "" && len(segments) > 0 && s.maxCandidates > 0 {
+		minChars, maxChars := fuzzyLengthRange(segments)
+		candidates, err = s.memory.Candidates(ctx, userID, memorySourceLang(sourceLang), memoryTargetLang(targetLang), minChars, maxChars, s.maxCandidates)
+		if err != nil {
+			return nil, err
+		}
+	}
+	analysis := translation.AnalyzeSegments(segments, exact, candidates, s.weights)
+	return &analysis, nil
+}
+
+// BillableCharacters charges matched characters at their band weight.
+func (s *MemoryService) BillableCharacters(characterCount int, analysis *translation.Analysis) int {
+	if analysis == nil {
+		return characterCount
+	}
+	return analysis.BillableCharacters(characterCount)
+}
+
+// fuzzyLengthRange returns the lengths a memory segment may have to still
+// reach a 75% match with one of segments.
+func fuzzyLengthRange(segments []string) (int, int) {
+	minChars, maxChars := 0, 0
+	for i, segment := range segments {
+		n := utf8.RuneCountInString(translation.NormalizeSegment(segment))
+		if i == 0 || n < minChars {
+			minChars = n
+		}
+		if n > maxChars {
+			maxChars = n
+		}
+	}
+	return minChars * 3 / 4, maxChars * 4 / 3
+}
+
+// Store records the segment pairs of a completed translation. Segments that
+// do not line up one to one are not stored.
+func (s *MemoryService) Store(ctx context.Context, translationEntity *models.Translation, sources, targets []string) error {
+	sourceLang := translationEntity.EffectiveSourceLang()
+	if s == nil || sourceLang == "" || len(sources) == 0 {
+		return nil
+	}
+	if len(sources) != len(targets) {
+		s.logger.Warn().Str("translation_id", translationEntity.ID).Int("sources", len(sources)).Int("targets", len(targets)).Msg("segments not aligned, skipping translation memory")
+		return nil
+	}
+	seen := make(map[string]bool, len(sources))
+	entries := make([]models.MemoryEntry, 0, len(sources))
+	for i, source := range sources {
+		source = translation.NormalizeSegment(source)
+		target := strings.TrimSpace(targets[i])
+		hash := translation.SegmentHash(source)
+		if source == "" || target == "" || seen[hash] {
+			continue
+		}
+		seen[hash] = true
+		entries = append(entries, models.MemoryEntry{
+			UserID:        translationEntity.UserID,
+			SourceLang:    memorySourceLang(sourceLang),
+			TargetLang:    memoryTargetLang(translationEntity.TargetLang),
+			SourceHash:    hash,
+			SourceText:    source,
+			TargetText:    target,
+			TranslationID: &translationEntity.ID,
+		})
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	return s.memory.Upsert(ctx, entries)
+}
+
+// TMXImportResult summarises a TMX import.
+type TMXImportResult struct {
+	Imported      int      `json:"imported"`
+	Skipped       int      `json:"skipped"`
+	LanguagePairs []string `json:"languagePairs"`
+}
+
+// memoryImportBatch bounds the entries written per transaction on import.
+const memoryImportBatch = 500
+
+// ImportTMX streams the segment pairs of a TMX document into the user's
+// memory. sourceLang and targetLang, when set, restrict the import to that
+// language pair; a target without region matches all its variants.
+func (s *MemoryService) ImportTMX(ctx context.Context, userID string, r io.Reader, sourceLang, targetLang string) (*TMXImportResult, error) {
+	result := &TMXImportResult{LanguagePairs: []string{}}
+	pairs := make(map[string]bool)
+	batch := make([]models.MemoryEntry, 0, memoryImportBatch)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := s.memory.Upsert(ctx, batch); err != nil {
+			return err
+		}
+		result.Imported += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+	err := translation.ReadTMX(r, func(unit translation.TMXUnit) error {
+		if !languageMatches(sourceLang, unit.SourceLang, true) || !languageMatches(targetLang, unit.TargetLang, false) {
+			result.Skipped++
+			return nil
+		}
+		source := translation.NormalizeSegment(unit.Source)
+		target := strings.TrimSpace(unit.Target)
+		if source == "" || target == "" {
+			result.Skipped++
+			return nil
+		}
+		entry := models.MemoryEntry{
+			UserID:     userID,
+			SourceLang: memorySourceLang(unit.SourceLang),
+			TargetLang: memoryTargetLang(unit.TargetLang),
+			SourceHash: translation.SegmentHash(source),
+			SourceText: source,
+			TargetText: target,
+		}
+		if pair := entry.SourceLang + ">" + entry.TargetLang; !pairs[pair] {
+			pairs[pair] = true
+			result.LanguagePairs = append(result.LanguagePairs, pair)
+		}
+		batch = append(batch, entry)
+		if len(batch) == memoryImportBatch {
+			return flush()
+		}
+		return nil
+	})
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if result.Imported == 0 {
+		return nil, errors.New("tmx contains no segments for the requested languages")
+	}
+	s.logger.Info().Str("user_id", userID).Int("imported", result.Imported).Int("skipped", result.Skipped).Msg("translation memory imported")
+	return result, nil
+}
+
+// languageMatches reports whether code satisfies the optional filter. Source
+// languages are compared by base language, like the memory keys them.
+func languageMatches(filter, code string, base bool) bool {
+	if strings.TrimSpace(filter) == "" {
+		return true
+	}
+	if base || !strings.ContainsAny(filter, "-_") {
+		return translation.BaseLanguage(filter) == translation.BaseLanguage(code)
+	}
+	return translation.NormalizeLanguageCode(filter) == code
+}
+
+// memorySourceLang keys source languages by base language; targets keep
+// their variant because EN-GB and EN-US translations differ.
+func memorySourceLang(code string) string {
+	return strings.ToUpper(translation.BaseLanguage(code))
+}
+
+func memoryTargetLang(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/models"
+	"github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/repository"
+	"github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/translation"
+)
+
+var (
+	// ErrModelNotFound is returned for unknown catalog keys.
+	ErrModelNotFound = errors.New("model not found")
+	// ErrModelInUse is returned when deleting a model translations still
+	// reference; queued jobs and invoices resolve their model by key.
+	ErrModelInUse = errors.New("model is used by translations; disable it instead")
+)
+
+// ModelCatalogService keeps the translation model catalog in the database
+// and publishes it to the translation package, so price and availability
+// changes apply without a restart.
+type ModelCatalogService struct {
+	models    *repository.ModelRepository
+	providers *translation.Registry
+	interval  time.Duration
+	logger    zerolog.Logger
+}
+
+// NewModelCatalogService constructs ModelCatalogService. interval is how
+// often the catalog is reloaded to pick up changes made by other instances.
+func NewModelCatalogService(modelRepo *repository.ModelRepository, providers *translation.Registry, interval time.Duration, logger zerolog.Logger) *ModelCatalogService {
+	return &ModelCatalogService{models: modelRepo, providers: providers, interval: interval, logger: logger}
+}
+
+// Load stores the built-in models the table does not know yet and publishes
+// the catalog. Stored models are left as edited, so a deleted built-in model
+// returns at the next start; disable it instead.
+func (s *ModelCatalogService) Load(ctx context.Context, builtin []translation.Model) error {
+	entries := make([]models.CatalogModel, 0, len(builtin))
+	for i, model := range builtin {
+		entry := model.CatalogEntry()
+		entry.SortOrder = i
+		entries = append(entries, entry)
+	}
+	if err := s.models.Seed(ctx, entries); err != nil {
+		return err
+	}
+	return s.Refresh(ctx)
+}
+
+// Start reloads the catalog periodically until ctx is done.
+func (s *ModelCatalogService) Start(ctx context.Context) {
+	if s.interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Refresh(ctx); err != nil {
+				s.logger.Warn().Err(err).Msg("failed to reload model catalog")
+			}
+		}
+	}
+}
+
+// Refresh publishes the stored catalog.
+func (s *ModelCatalogService) Refresh(ctx context.Context) error {
+	entries, err := s.models.List(ctx)
+	if err != nil {
+		return err
+	}
+	catalog := make([]translation.Model, 0, len(entries))
+	for _, entry := range entries {
+		catalog = append(catalog, translation.ModelFromCatalog(entry))
+	}
+	translation.SetCatalog(catalog)
+	return nil
+}
+
+// List returns every stored model including disabled ones.
+func (s *ModelCatalogService) List(ctx context.Context) ([]models.CatalogModel, error) {
+	return s.models.List(ctx)
+}
+
+// Create adds a model to the catalog.
+func (s *ModelCatalogService) Create(ctx context.Context, entry models.CatalogModel) (*models.CatalogModel, error) {
+	if err := s.validate(&entry); err != nil {
+		return nil, err
+	}
+	if translation.GetModelByKey(entry.Key) != nil {
+		return nil, fmt.Errorf("model %s already exists", entry.Key)
+	}
+	created, err := s.models.Create(ctx, &entry)
+	if err != nil {
+		return nil, err
+	}
+	return created, s.Refresh(ctx)
+}
+
+// Update replaces the model stored under key.
+func (s *ModelCatalogService) Update(ctx context.Context, key string, entry models.CatalogModel) (*models.CatalogModel, error) {
+	entry.Key = key
+	if err := s.validate(&entry); err != nil {
+		return nil, err
+	}
+	updated, err := s.models.Update(ctx, &entry)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrModelNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return updated, s.Refresh(ctx)
+}
+
+// Delete removes a model no translation uses from the catalog. Models in
+// use can only be disabled, which keeps them resolvable for the worker and
+// invoices.
+func (s *ModelCatalogService) Delete(ctx context.Context, key string) error {
+	inUse, err := s.models.InUse(ctx, key)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return ErrModelInUse
+	}
+	err = s.models.Delete(ctx, key)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrModelNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return s.Refresh(ctx)
+}
+
+// validate normalises entry and checks that its routes are served by a
+// configured provider.
+func (s *ModelCatalogService) validate(entry *models.CatalogModel) error {
+	entry.Key = strings.TrimSpace(entry.Key)
+	entry.DisplayName = strings.TrimSpace(entry.DisplayName)
+	entry.Currency = strings.ToUpper(strings.TrimSpace(entry.Currency))
+	if entry.Currency == "" {
+		entry.Currency = "EUR"
+	}
+	switch {
+	case entry.Key == "":
+		return errors.New("model key required")
+	case entry.DisplayName == "":
+		return errors.New("display name required")
+	case entry.Engine == "":
+		return errors.New("engine required")
+	case entry.PricePer1860 <= 0:
+		return errors.New("price must be positive")
+	case entry.MaxCharacters < 0:
+		return errors.New("max characters must not be negative")
+	}
+	routes := append(models.ModelRoutes{{Provider: entry.Provider, Engine: entry.Engine}}, entry.Fallbacks...)
+	for _, route := range routes {
+		if _, err := s.providers.Get(translation.ProviderType(route.Provider)); err != nil {
+			return fmt.Errorf("provider %q is not configured", route.Provider)
+		}
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/stripe/stripe-go/v75"
@@ -47,9 +48,16 @@ func (s *PaymentService) StartTranslationCheckout(ctx context.Context, userID, t
 	if translation.Status != models.TranslationPending {
 		return nil, fmt.Errorf("translation %s is not pending payment", translationID)
 	}
+	if translation.OrderID != nil {
+		return nil, fmt.Errorf("translation %s is paid with order %s", translationID, *translation.OrderID)
+	}
+	if err := s.translateSvc.EnsureCapacity(translation); err != nil {
+		return nil, err
+	}
 
 	session, err := s.stripeClient.CreateTranslationCheckoutSession(ctx, payment.TranslationSessionParams{
 		AmountCents: translation.PriceCents,
+		Currency:    translation.Currency,
 		SuccessURL:  successURL,
 		CancelURL:   cancelURL,
 		Metadata: map[string]string{
@@ -76,6 +84,60 @@ func (s *PaymentService) StartTranslationCheckout(ctx context.Context, userID, t
 	return session, nil
 }
 
+// StartOrderCheckout creates one Stripe checkout covering every target
+// language of an order.
+func (s *PaymentService) StartOrderCheckout(ctx context.Context, userID, orderID, successURL, cancelURL string) (*stripe.CheckoutSession, error) {
+	order, err := s.translateSvc.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID != userID {
+		return nil, fmt.Errorf("order %s does not belong to user", orderID)
+	}
+	if order.Status != models.OrderPending {
+		return nil, fmt.Errorf("order %s is not pending payment", orderID)
+	}
+	if err := s.translateSvc.EnsureOrderCapacity(ctx, order.TranslationOrder); err != nil {
+		return nil, err
+	}
+
+	items := make([]payment.LineItem, 0, len(order.Translations))
+	for _, child := range order.Translations {
+		items = append(items, payment.LineItem{
+			Name:        fmt.Sprintf("Kaminskyi Übersetzungsdienst (%s)", strings.ToUpper(child.TargetLang)),
+			AmountCents: child.PriceCents,
+		})
+	}
+	session, err := s.stripeClient.CreateTranslationCheckoutSession(ctx, payment.TranslationSessionParams{
+		AmountCents: order.PriceCents,
+		Currency:    order.Currency,
+		SuccessURL:  successURL,
+		CancelURL:   cancelURL,
+		Items:       items,
+		Metadata: map[string]string{
+			"order_id": order.ID,
+			"user_id":  order.UserID,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.payments.Create(ctx, &models.Payment{
+		UserID:              order.UserID,
+		OrderID:             &order.ID,
+		AmountCents:         order.PriceCents,
+		Currency:            order.Currency,
+		StripeSessionID:     session.ID,
+		StripePaymentIntent: extractPaymentIntentID(session),
+		Status:              string(stripe.CheckoutSessionPaymentStatusUnpaid),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
 // StartSubscriptionCheckout starts Stripe session for premium subscription.
 func (s *PaymentService) StartSubscriptionCheckout(ctx context.Context, userID, successURL, cancelURL string) (*stripe.CheckoutSession, error) {
 	if s.premiumPriceID == "" {
@@ -118,6 +180,9 @@ func (s *PaymentService) MarkPaymentSucceeded(ctx context.Context, sessionID str
 		return err
 	}
 
+	if paymentRecord.OrderID != nil {
+		return s.translateSvc.QueueOrder(ctx, *paymentRecord.OrderID)
+	}
 	if paymentRecord.TranslationID != nil {
 		return s.translateSvc.QueueTranslation(ctx, *paymentRecord.TranslationID)
 	}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/models"
+	"github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/repository"
+	"github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/translation"
+	"github.com/olehkaminskyi/kaminskyi-language-intelligence/pkg/utils"
+)
+
+// defaultPremiumDiscount applies while no price list is active.
+const defaultPremiumDiscount = 0.20
+
+// PriceQuote is the price applied to a translation, stored on it as a
+// snapshot so later price changes do not alter its invoice.
+type PriceQuote struct {
+	PriceListVersion *int
+	UnitPrice        float64
+	Currency         string
+	Discount         float64
+}
+
+// PriceCents prices the given number of billable characters.
+func (q PriceQuote) PriceCents(characters int) int64 {
+	return utils.CalculatePriceCents(characters, q.UnitPrice, q.Discount)
+}
+
+// PricingService resolves model prices from versioned price lists.
+type PricingService struct {
+	priceLists *repository.PriceListRepository
+	logger     zerolog.Logger
+}
+
+// NewPricingService constructs PricingService.
+func NewPricingService(priceLists *repository.PriceListRepository, logger zerolog.Logger) *PricingService {
+	return &PricingService{priceLists: priceLists, logger: logger}
+}
+
+// Quote prices model for user with the price list valid now. Models the
+// list does not price, or no active list at all, fall back to the catalog
+// price without a version.
+func (s *PricingService) Quote(ctx context.Context, model translation.Model, user *models.User) (PriceQuote, error) {
+	quote := PriceQuote{UnitPrice: model.PricePer1860, Currency: model.Currency}
+	premiumDiscount := defaultPremiumDiscount
+	list, err := s.priceLists.Active(ctx, time.Now().UTC())
+	if err != nil {
+		return quote, fmt.Errorf("price list: %w", err)
+	}
+	if list != nil {
+		premiumDiscount = list.PremiumDiscount
+		for _, item := range list.Items {
+			if item.ModelKey == model.Key {
+				version := list.Version
+				quote.PriceListVersion = &version
+				quote.UnitPrice = item.PricePer1860
+				quote.Currency = item.Currency
+				break
+			}
+		}
+	}
+	if quote.Currency == "" {
+		quote.Currency = "EUR"
+	}
+	if user != nil && user.Subscription == models.SubscriptionPremium {
+		quote.Discount = premiumDiscount
+	}
+	return quote, nil
+}
+
+// ListPriceLists returns every price list, newest version first.
+func (s *PricingService) ListPriceLists(ctx context.Context) ([]models.PriceList, error) {
+	return s.priceLists.List(ctx)
+}
+
+// CreatePriceList stores list as the next version. It takes effect at
+// ValidFrom, which defaults to now.
+func (s *PricingService) CreatePriceList(ctx context.Context, list models.PriceList) (*models.PriceList, error) {
+	if list.ValidFrom.IsZero() {
+		list.ValidFrom = time.Now().UTC()
+	}
+	if list.ValidUntil != nil && !list.ValidUntil.After(list.ValidFrom) {
+		return nil, errors.New("validUntil must be after validFrom")
+	}
+	if list.PremiumDiscount < 0 || list.PremiumDiscount >= 1 {
+		return nil, errors.New("premium discount must be between 0 and 1")
+	}
+	if len(list.Items) == 0 {
+		return nil, errors.New("price list has no items")
+	}
+	seen := make(map[string]bool, len(list.Items))
+	for i := range list.Items {
+		item := &list.Items[i]
+		item.Currency = strings.ToUpper(strings.TrimSpace(item.Currency))
+		if item.Currency == "" {
+			item.Currency = "EUR"
+		}
+		switch {
+		case translation.GetModelByKey(item.ModelKey) == nil:
+			return nil, fmt.Errorf("unknown model %s", item.ModelKey)
+		case seen[item.ModelKey]:
+			return nil, fmt.Errorf("model %s priced twice", item.ModelKey)
+		case item.PricePer1860 <= 0:
+			return nil, fmt.Errorf("model %s: price must be positive", item.ModelKey)
+		}
+		seen[item.ModelKey] = true
+	}
+	created, err := s.priceLists.Create(ctx, &list)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info().Int("version", created.Version).Time("valid_from", created.ValidFrom).Msg("price list created")
+	return created, nil
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/translation"
+)
+
+// ErrQuotaExhausted is returned when a job would exceed provider quota.
+var ErrQuotaExhausted = errors.New("translation capacity temporarily exhausted, please try again later")
+
+var usageMetrics = expvar.NewMap("provider_usage")
+
+// UsageSource reports character usage for one provider API key.
+type UsageSource interface {
+	KeyID() string
+	Usage(ctx context.Context) (*translation.Usage, error)
+}
+
+// ProviderUsage is the last known usage of one API key.
+type ProviderUsage struct {
+	Key            string                   `json:"key"`
+	Provider       translation.ProviderType `json:"provider"`
+	CharacterCount int64                    `json:"characterCount"`
+	CharacterLimit int64                    `json:"characterLimit"`
+	Remaining      int64                    `json:"remaining"`
+	CheckedAt      time.Time                `json:"checkedAt"`
+	Error          string                   `json:"error,omitempty"`
+}
+
+type usageEntry struct {
+	provider translation.ProviderType
+	source   UsageSource
+}
+
+// QuotaMonitor polls provider usage and gates work that would exceed it.
+type QuotaMonitor struct {
+	interval time.Duration
+	margin   int64
+	logger   zerolog.Logger
+
+	mu      sync.RWMutex
+	sources []usageEntry
+	usage   map[string]ProviderUsage
+}
+
+// NewQuotaMonitor constructs a monitor. margin characters are kept in reserve
+// because usage lags behind jobs that are already queued.
+func NewQuotaMonitor(interval time.Duration, margin int64, logger zerolog.Logger) *QuotaMonitor {
+	return &QuotaMonitor{
+		interval: interval,
+		margin:   margin,
+		logger:   logger,
+		usage:    make(map[string]ProviderUsage),
+	}
+}
+
+// AddSource registers an API key to poll for the provider.
+func (m *QuotaMonitor) AddSource(provider translation.ProviderType, source UsageSource) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sources = append(m.sources, usageEntry{provider: provider, source: source})
+}
+
+// Start polls usage until ctx is cancelled.
+func (m *QuotaMonitor) Start(ctx context.Context) {
+	m.Refresh(ctx)
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.Refresh(ctx)
+		}
+	}
+}
+
+// Refresh fetches usage for every registered key.
+func (m *QuotaMonitor) Refresh(ctx context.Context) {
+	m.mu.RLock()
+	sources := append([]usageEntry(nil), m.sources...)
+	m.mu.RUnlock()
+
+	for _, entry := range sources {
+		key := entry.source.KeyID()
+		snapshot := ProviderUsage{Key: key, Provider: entry.provider, CheckedAt: time.Now().UTC()}
+		usage, err := entry.source.Usage(ctx)
+		if err != nil {
+			m.logger.Warn().Err(err).Str("key", key).Msg("failed to fetch provider usage")
+			snapshot.Error = err.Error()
+		} else {
+			snapshot.CharacterCount = usage.CharacterCount
+			snapshot.CharacterLimit = usage.CharacterLimit
+			snapshot.Remaining = usage.Remaining()
+			usageMetrics.Set(key+".character_count", expvarInt(usage.CharacterCount))
+			usageMetrics.Set(key+".character_limit", expvarInt(usage.CharacterLimit))
+			usageMetrics.Set(key+".remaining", expvarInt(snapshot.Remaining))
+		}
+		m.mu.Lock()
+		m.usage[key] = snapshot
+		m.mu.Unlock()
+	}
+}
+
+// Snapshot returns the last known usage of every key.
+func (m *QuotaMonitor) Snapshot() []ProviderUsage {
+	if m == nil {
+		return nil
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	result := make([]ProviderUsage, 0, len(m.usage))
+	for _, u := range m.usage {
+		result = append(result, u)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result
+}
+
+// Remaining returns characters left across all healthy keys of a provider.
+// The second value is false when no usage data is available.
+func (m *QuotaMonitor) Remaining(provider translation.ProviderType) (int64, bool) {
+	if m == nil {
+		return 0, false
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var remaining int64
+	known := false
+	for _, u := range m.usage {
+		if u.Provider != provider || u.Error != "" {
+			continue
+		}
+		remaining += u.Remaining
+		known = true
+	}
+	return remaining, known
+}
+
+// Admit checks that characters fit into the provider's remaining quota.
+// Unmonitored providers and unknown usage are admitted.
+func (m *QuotaMonitor) Admit(provider translation.ProviderType, characters int) error {
+	if m == nil {
+		return nil
+	}
+	remaining, known := m.Remaining(provider)
+	if !known {
+		return nil
+	}
+	if int64(characters) > remaining-m.margin {
+		m.logger.Warn().Str("provider", string(provider)).Int("characters", characters).Int64("remaining", remaining).Msg("quota admission refused")
+		return fmt.Errorf("%w (%s)", ErrQuotaExhausted, provider)
+	}
+	return nil
+}
+
+// RetryDelay is how long deferred work waits before the next attempt.
+func (m *QuotaMonitor) RetryDelay() time.Duration {
+	if m == nil || m.interval <= 0 {
+		return time.Hour
+	}
+	return 2 * m.interval
+}
+
+func expvarInt(v int64) *expvar.Int {
+	i := new(expvar.Int)
+	i.Set(v)
+	return i
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/models"
+	"github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/repository"
+	"github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/translation"
+)
+
+// SegmentService lets users post-edit the segments of a translation.
+type SegmentService struct {
+	edits        *repository.SegmentEditRepository
+	translateSvc *TranslationService
+	memory       *MemoryService
+	logger       zerolog.Logger
+}
+
+// NewSegmentService constructs SegmentService.
+func NewSegmentService(edits *repository.SegmentEditRepository, translateSvc *TranslationService, memory *MemoryService, logger zerolog.Logger) *SegmentService {
+	return &SegmentService{edits: edits, translateSvc: translateSvc, memory: memory, logger: logger}
+}
+
+// Segment is an aligned source/target pair of a translated document.
+type Segment struct {
+	Index    int        `json:"index"`
+	Source   string     `json:"source"`
+	Target   string     `json:"target"`
+	EditedBy *string    `json:"editedBy,omitempty"`
+	EditedAt *time.Time `json:"editedAt,omitempty"`
+}
+
+// SegmentEditInput replaces the target of the segment at Index.
+type SegmentEditInput struct {
+	Index  int    `json:"index"`
+	Target string `json:"target"`
+}
+
+// List returns the segments of the current document with their last edit.
+func (s *SegmentService) List(ctx context.Context, t *models.Translation) ([]Segment, error) {
+	sources, targets, err := s.segments(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+	return s.describe(ctx, t.ID, sources, targets)
+}
+
+// Edit stores the edits and writes the edited segments into a new version
+// of the translated document.
+func (s *SegmentService) Edit(ctx context.Context, t *models.Translation, editorID string, inputs []SegmentEditInput) ([]Segment, error) {
+	if len(inputs) == 0 {
+		return nil, errors.New("no segments to edit")
+	}
+	sources, targets, err := s.segments(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+	edits := make([]models.SegmentEdit, 0, len(inputs))
+	for _, input := range inputs {
+		if input.Index < 0 || input.Index >= len(sources) {
+			return nil, fmt.Errorf("segment %d out of range", input.Index)
+		}
+		target := strings.TrimSpace(input.Target)
+		if target == "" {
+			return nil, fmt.Errorf("segment %d: target required", input.Index)
+		}
+		if target == targets[input.Index] {
+			continue
+		}
+		edits = append(edits, models.SegmentEdit{
+			TranslationID: t.ID,
+			SegmentIndex:  input.Index,
+			SourceText:    sources[input.Index],
+			PreviousText:  targets[input.Index],
+			TargetText:    target,
+			EditedBy:      &editorID,
+		})
+		targets[input.Index] = target
+	}
+	if len(edits) == 0 {
+		return s.describe(ctx, t.ID, sources, targets)
+	}
+
+	source, err := s.translateSvc.readFile(ctx, t.ID, models.FileKindSource)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.translateSvc.readFile(ctx, t.ID, models.FileKindReviewed, models.FileKindTranslated)
+	if err != nil {
+		return nil, err
+	}
+	edited, err := translation.ReviseDocument(ctx, t.OriginalFilename, t.TargetLang, source, current, targets)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.translateSvc.storeVersion(ctx, t, models.FileKindTranslated, edited); err != nil {
+		return nil, err
+	}
+	if err := s.edits.Create(ctx, edits); err != nil {
+		return nil, err
+	}
+	if err := s.memory.Store(ctx, t, sources, targets); err != nil {
+		s.logger.Warn().Err(err).Str("translation_id", t.ID).Msg("failed to update translation memory")
+	}
+	s.logger.Info().Str("translation_id", t.ID).Int("segments", len(edits)).Msg("translation post-edited")
+	return s.describe(ctx, t.ID, sources, targets)
+}
+
+func (s *SegmentService) segments(ctx context.Context, t *models.Translation) ([]string, []string, error) {
+	if t.Status != models.TranslationCompleted || t.TranslatedFilename == nil {
+		return nil, nil, errors.New("translation not ready")
+	}
+	if !translation.CanReassemble(t.OriginalFilename) {
+		return nil, nil, fmt.Errorf("segments are not available for %s files", filepath.Ext(t.OriginalFilename))
+	}
+	return s.translateSvc.alignedSegments(ctx, t)
+}
+
+// describe pairs the segments with the latest edit of each.
+func (s *SegmentService) describe(ctx context.Context, translationID string, sources, targets []string) ([]Segment, error) {
+	edits, err := s.edits.ListByTranslation(ctx, translationID)
+	if err != nil {
+		return nil, err
+	}
+	segments := make([]Segment, len(sources))
+	for i := range sources {
+		segments[i] = Segment{Index: i, Source: sources[i], Target: targets[i]}
+	}
+	for i := range edits {
+		edit := &edits[i]
+		if edit.SegmentIndex < len(segments) {
+			segments[edit.SegmentIndex].EditedBy = edit.EditedBy
+			segments[edit.SegmentIndex].EditedAt = &edit.CreatedAt
+		}
+	}
+	return segments, nil
+}
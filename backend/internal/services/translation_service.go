@@ -6,7 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -24,24 +26,34 @@ import (
 // TranslationService orchestrates translation lifecycle.
 type TranslationService struct {
 	translations *repository.TranslationRepository
+	orders       *repository.OrderRepository
 	files        *repository.FileRepository
 	storage      storage.Provider
 	queue        *queue.Client
-	deepl        *translation.DeepLClient
-	otranslator  *translation.OTranslatorClient
+	providers    *translation.Registry
+	glossaries   *GlossaryService
+	languages    *LanguageService
+	memory       *MemoryService
+	pricing      *PricingService
+	quota        *QuotaMonitor
 	logger       zerolog.Logger
 	retention    time.Duration
 }
 
 // NewTranslationService constructs service.
-func NewTranslationService(translations *repository.TranslationRepository, files *repository.FileRepository, storage storage.Provider, queueClient *queue.Client, deepl *translation.DeepLClient, otranslator *translation.OTranslatorClient, retention time.Duration, logger zerolog.Logger) *TranslationService {
+func NewTranslationService(translations *repository.TranslationRepository, orders *repository.OrderRepository, files *repository.FileRepository, storage storage.Provider, queueClient *queue.Client, providers *translation.Registry, glossaries *GlossaryService, languages *LanguageService, memory *MemoryService, pricing *PricingService, quota *QuotaMonitor, retention time.Duration, logger zerolog.Logger) *TranslationService {
 	return &TranslationService{
 		translations: translations,
+		orders:       orders,
 		files:        files,
 		storage:      storage,
 		queue:        queueClient,
-		deepl:        deepl,
-		otranslator:  otranslator,
+		providers:    providers,
+		glossaries:   glossaries,
+		languages:    languages,
+		memory:       memory,
+		pricing:      pricing,
+		quota:        quota,
 		logger:       logger,
 		retention:    retention,
 	}
@@ -56,6 +68,7 @@ type CreateTranslationInput struct {
 	SourceLang  string
 	TargetLang  string
 	ModelKey    string
+	GlossaryID  string
 	Options     map[string]interface{}
 	StripTags   bool
 }
@@ -64,88 +77,400 @@ type CreateTranslationInput struct {
 type CreateTranslationResult struct {
 	Translation *models.Translation
 	Model       translation.Model
+	Warnings    []string
+	// Analysis is the translation memory analysis, nil for formats that
+	// are not segmented.
+	Analysis *translation.Analysis
 }
 
+// ErrDocumentTooLarge is returned when a document exceeds the character
+// limit of the selected model.
+var ErrDocumentTooLarge = errors.New("document exceeds the character limit of the model")
+
+// detectionWarnConfidence is the confidence above which a detected source
+// language that contradicts the selected one is reported to the user.
+const detectionWarnConfidence = 0.5
+
 // CreateTranslation validates input, persists metadata and schedules payment.
 func (s *TranslationService) CreateTranslation(ctx context.Context, input CreateTranslationInput) (*CreateTranslationResult, error) {
+	draft, err := s.prepareTranslation(ctx, input, []string{input.TargetLang})
+	if err != nil {
+		return nil, err
+	}
+	translationEntity, err := s.createChild(ctx, input, draft, input.TargetLang, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.storeSource(ctx, input, draft, translationEntity.ID, []*models.Translation{translationEntity}); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info().Str("translation_id", translationEntity.ID).Str("model", input.ModelKey).Int("characters", draft.characterCount).Msg("translation created")
+
+	return &CreateTranslationResult{Translation: translationEntity, Model: draft.model, Warnings: draft.warnings, Analysis: draft.analyses[input.TargetLang]}, nil
+}
+
+// CreateOrderResult describes a created multi-language order.
+type CreateOrderResult struct {
+	Order        *models.TranslationOrder
+	Translations []*models.Translation
+	Model        translation.Model
+	Warnings     []string
+	// Analyses holds the translation memory analysis per target language.
+	Analyses map[string]*translation.Analysis
+}
+
+// CreateOrder translates one upload into several target languages. Every
+// language becomes a child translation sharing the stored source file; the
+// order is paid for with a single checkout.
+func (s *TranslationService) CreateOrder(ctx context.Context, input CreateTranslationInput, targetLangs []string) (*CreateOrderResult, error) {
+	targetLangs = uniqueLanguages(targetLangs)
+	draft, err := s.prepareTranslation(ctx, input, targetLangs)
+	if err != nil {
+		return nil, err
+	}
+	order := &models.TranslationOrder{
+		ID:               uuid.NewString(),
+		UserID:           input.User.ID,
+		SourceLang:       draft.sourceLang,
+		ModelKey:         input.ModelKey,
+		OriginalFilename: input.Filename,
+		Currency:         draft.quote.Currency,
+	}
+	for _, targetLang := range targetLangs {
+		order.PriceCents += draft.priceCents(targetLang)
+	}
+	order, err = s.orders.Create(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+	children, err := s.createChildren(ctx, input, draft, targetLangs, order.ID)
+	if err != nil {
+		// Deleting the order removes the translations created so far, so no
+		// partial order is left to pay for or process.
+		if derr := s.orders.Delete(context.WithoutCancel(ctx), order.ID); derr != nil {
+			s.logger.Error().Err(derr).Str("order_id", order.ID).Msg("failed to remove incomplete order")
+		}
+		return nil, err
+	}
+
+	s.logger.Info().Str("order_id", order.ID).Str("model", input.ModelKey).Strs("targets", targetLangs).Int("characters", draft.characterCount).Msg("translation order created")
+
+	return &CreateOrderResult{Order: order, Translations: children, Model: draft.model, Warnings: draft.warnings, Analyses: draft.analyses}, nil
+}
+
+// createChildren creates the translation of every target language of an
+// order and stores the source for them.
+func (s *TranslationService) createChildren(ctx context.Context, input CreateTranslationInput, draft *translationDraft, targetLangs []string, orderID string) ([]*models.Translation, error) {
+	children := make([]*models.Translation, 0, len(targetLangs))
+	for _, targetLang := range targetLangs {
+		child, err := s.createChild(ctx, input, draft, targetLang, &orderID)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+	if err := s.storeSource(ctx, input, draft, orderID, children); err != nil {
+		return nil, err
+	}
+	return children, nil
+}
+
+// translationDraft holds the validated, target-independent part of new
+// translations.
+type translationDraft struct {
+	model          translation.Model
+	sourceLang     string
+	glossary       *models.Glossary
+	characterCount int
+	detection      translation.Detection
+	warnings       []string
+	deleteAfter    *time.Time
+	// analyses holds the translation memory analysis per target language.
+	analyses map[string]*translation.Analysis
+	memory   *MemoryService
+	// options are the validated options with model defaults applied.
+	options map[string]interface{}
+	// targetOptions are the options adapted to each target language.
+	targetOptions map[string]map[string]interface{}
+	quote         PriceQuote
+}
+
+// prepareTranslation validates input for every target language, counts the
+// characters once and admits the combined volume against provider quota.
+func (s *TranslationService) prepareTranslation(ctx context.Context, input CreateTranslationInput, targetLangs []string) (*translationDraft, error) {
 	if input.User == nil {
 		return nil, errors.New("user required")
 	}
-	if input.TargetLang == "" {
+	if len(targetLangs) == 0 || targetLangs[0] == "" {
 		return nil, errors.New("target language required")
 	}
 	model := translation.GetModelByKey(input.ModelKey)
 	if model == nil {
 		return nil, fmt.Errorf("unknown model %s", input.ModelKey)
 	}
+	if model.Disabled {
+		return nil, fmt.Errorf("model %s is not available", input.ModelKey)
+	}
+	if _, err := s.providers.ForModel(*model); err != nil {
+		return nil, fmt.Errorf("model %s unavailable: %w", input.ModelKey, err)
+	}
+	requested := input.Options
+	options, err := validateOptions(*model, &input)
+	if err != nil {
+		return nil, err
+	}
+	targetOptions := make(map[string]map[string]interface{}, len(targetLangs))
+	for _, targetLang := range targetLangs {
+		if err := s.languages.Validate(ctx, *model, input.SourceLang, targetLang); err != nil {
+			return nil, err
+		}
+		language, _ := s.languages.TargetLanguage(ctx, *model, targetLang)
+		if targetOptions[targetLang], err = translation.OptionsForTarget(options, requested, language); err != nil {
+			return nil, err
+		}
+	}
+	quote, err := s.pricing.Quote(ctx, *model, input.User)
+	if err != nil {
+		return nil, err
+	}
+	draft := &translationDraft{model: *model, sourceLang: input.SourceLang, options: options, targetOptions: targetOptions, quote: quote}
+	if input.GlossaryID != "" {
+		glossary, err := s.resolveGlossary(ctx, input, *model, targetLangs)
+		if err != nil {
+			return nil, err
+		}
+		// DeepL only applies glossaries when the source language is explicit.
+		if draft.sourceLang == "" {
+			draft.sourceLang = glossary.SourceLang
+		}
+		draft.glossary = glossary
+	}
 	text, err := ExtractText(input.Filename, input.Data)
 	if err != nil {
 		return nil, fmt.Errorf("extract text: %w", err)
 	}
-	characterCount := utils.CountCharacters(text, input.StripTags)
-	if characterCount == 0 {
+	draft.characterCount = utils.CountCharacters(text, input.StripTags)
+	if draft.characterCount == 0 {
 		return nil, errors.New("document appears to be empty")
 	}
-
-	discount := 0.0
-	if input.User.Subscription == models.SubscriptionPremium {
-		discount = 0.20
+	if err := checkCharacterLimit(*model, input.Filename, options, draft.characterCount); err != nil {
+		return nil, err
+	}
+	draft.detection = translation.DetectLanguage(text)
+	draft.warnings = s.detectionWarnings(ctx, *model, input.SourceLang, draft.detection)
+	if err := s.matchMemory(ctx, input, draft, targetLangs); err != nil {
+		return nil, err
+	}
+	if err := s.quota.Admit(model.Provider, draft.characterCount*len(targetLangs)); err != nil {
+		return nil, err
 	}
-
-	priceCents := utils.CalculatePriceCents(characterCount, model.PricePer1860, discount)
-	translationID := uuid.NewString()
-	deleteAfter := (*time.Time)(nil)
 	if input.User.Subscription != models.SubscriptionPremium {
 		expiry := time.Now().Add(s.retention)
-		deleteAfter = &expiry
+		draft.deleteAfter = &expiry
 	}
+	return draft, nil
+}
 
-	translationEntity := &models.Translation{
-		ID:               translationID,
-		UserID:           input.User.ID,
-		SourceLang:       input.SourceLang,
-		TargetLang:       input.TargetLang,
-		ModelKey:         input.ModelKey,
-		CharacterCount:   characterCount,
-		PriceCents:       priceCents,
-		Currency:         "EUR",
-		Options:          models.JSONB(input.Options),
-		Status:           models.TranslationPending,
-		QueueTaskID:      "",
-		OriginalFilename: input.Filename,
-		DeleteAfter:      deleteAfter,
+// validateOptions checks the options against the model's schema. A glossary
+// may be selected by GlossaryID or the glossary option; the choice is moved
+// to input.GlossaryID, as createChild records the resolved glossary itself.
+func validateOptions(model translation.Model, input *CreateTranslationInput) (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(input.Options)+1)
+	for key, value := range input.Options {
+		values[key] = value
+	}
+	if input.GlossaryID != "" {
+		values["glossary"] = input.GlossaryID
 	}
+	options, err := model.ValidateOptions(values)
+	if err != nil {
+		return nil, err
+	}
+	if glossaryID, ok := options["glossary"].(string); ok {
+		input.GlossaryID = glossaryID
+	}
+	delete(options, "glossary")
+	return options, nil
+}
+
+// checkCharacterLimit rejects documents above the model's limit unless the
+// user asked for them to be translated in parts and the format can be split.
+func checkCharacterLimit(model translation.Model, filename string, options map[string]interface{}, characters int) error {
+	if model.MaxCharacters <= 0 || characters <= model.MaxCharacters {
+		return nil
+	}
+	split, _ := options["split_oversized"].(bool)
+	if !split {
+		return fmt.Errorf("%w: %d characters, %s accepts at most %d; set the split_oversized option to translate it in parts", ErrDocumentTooLarge, characters, model.DisplayName, model.MaxCharacters)
+	}
+	if !translation.CanReassemble(filename) {
+		return fmt.Errorf("%w: %s documents cannot be split into parts", ErrDocumentTooLarge, filepath.Ext(filename))
+	}
+	return nil
+}
 
-	translationEntity, err = s.translations.Create(ctx, translationEntity)
+// matchMemory analyses the document segments against the user's
+// translation memory. Only formats the worker reassembles losslessly reuse
+// memory, so only those are analysed and discounted. DOCX and EPUB go to the
+// provider whole and are priced in full.
+func (s *TranslationService) matchMemory(ctx context.Context, input CreateTranslationInput, draft *translationDraft, targetLangs []string) error {
+	draft.memory = s.memory
+	draft.analyses = make(map[string]*translation.Analysis, len(targetLangs))
+	if s.memory == nil || !translation.ReassemblesLosslessly(input.Filename) {
+		return nil
+	}
+	sourceLang := draft.sourceLang
+	if sourceLang == "" {
+		sourceLang = draft.detection.Language
+	}
+	segments, err := translation.DocumentSegments(input.Filename, input.Data)
+	if err != nil {
+		s.logger.Warn().Err(err).Str("filename", input.Filename).Msg("failed to segment document for translation memory")
+		return nil
+	}
+	for _, targetLang := range targetLangs {
+		analysis, err := s.memory.Analyze(ctx, input.User.ID, sourceLang, targetLang, segments)
+		if err != nil {
+			return fmt.Errorf("translation memory: %w", err)
+		}
+		draft.analyses[targetLang] = analysis
+	}
+	return nil
+}
+
+// resolveGlossary checks the glossary against the request. An order applies
+// it to the target languages it was built for, of which there must be one.
+func (s *TranslationService) resolveGlossary(ctx context.Context, input CreateTranslationInput, model translation.Model, targetLangs []string) (*models.Glossary, error) {
+	if len(targetLangs) == 1 {
+		return s.glossaries.ResolveForTranslation(ctx, input.User.ID, input.GlossaryID, model, input.SourceLang, targetLangs[0])
+	}
+	glossary, err := s.glossaries.Get(ctx, input.User.ID, input.GlossaryID)
 	if err != nil {
 		return nil, err
 	}
+	for _, targetLang := range targetLangs {
+		if translation.BaseLanguage(targetLang) == glossary.TargetLang {
+			return s.glossaries.ResolveForTranslation(ctx, input.User.ID, input.GlossaryID, model, input.SourceLang, targetLang)
+		}
+	}
+	return nil, fmt.Errorf("glossary target language %s does not match any requested target language", glossary.TargetLang)
+}
+
+// priceCents prices one target language of the draft; segments matched in
+// the translation memory are charged at their match band weight.
+func (d *translationDraft) priceCents(targetLang string) int64 {
+	characters := d.memory.BillableCharacters(d.characterCount, d.analyses[targetLang])
+	return d.quote.PriceCents(characters)
+}
 
-	storageKey := s.buildStorageKey(input.User.ID, translationID, "source", input.Filename)
+func (d *translationDraft) matchedCharacters(targetLang string) int {
+	if analysis := d.analyses[targetLang]; analysis != nil {
+		return analysis.MatchedCharacters()
+	}
+	return 0
+}
+
+// createChild persists the translation of the draft into targetLang.
+func (s *TranslationService) createChild(ctx context.Context, input CreateTranslationInput, draft *translationDraft, targetLang string, orderID *string) (*models.Translation, error) {
+	options := models.JSONB{}
+	for key, value := range draft.targetOptions[targetLang] {
+		options[key] = value
+	}
+	// Provider glossary IDs are only ever set from an owned glossary.
+	if draft.glossary != nil && translation.BaseLanguage(targetLang) == draft.glossary.TargetLang {
+		options["glossary"] = draft.glossary.ID
+		options["glossary_id"] = draft.glossary.ProviderGlossaryID
+	}
+
+	translationEntity := &models.Translation{
+		ID:                  uuid.NewString(),
+		UserID:              input.User.ID,
+		OrderID:             orderID,
+		SourceLang:          draft.sourceLang,
+		TargetLang:          targetLang,
+		ModelKey:            input.ModelKey,
+		CharacterCount:      draft.characterCount,
+		TMMatchedCharacters: draft.matchedCharacters(targetLang),
+		PriceCents:          draft.priceCents(targetLang),
+		Currency:            draft.quote.Currency,
+		PriceListVersion:    draft.quote.PriceListVersion,
+		UnitPrice:           draft.quote.UnitPrice,
+		PriceDiscount:       draft.quote.Discount,
+		Options:             options,
+		Status:              models.TranslationPending,
+		QueueTaskID:         "",
+		OriginalFilename:    input.Filename,
+		DeleteAfter:         draft.deleteAfter,
+	}
+	if draft.detection.Language != "" {
+		translationEntity.DetectedSourceLang = &draft.detection.Language
+		translationEntity.DetectionConfidence = &draft.detection.Confidence
+	}
+	return s.translations.Create(ctx, translationEntity)
+}
+
+// storeSource saves the upload once under ownerID and links it to every
+// translation as its source file.
+func (s *TranslationService) storeSource(ctx context.Context, input CreateTranslationInput, draft *translationDraft, ownerID string, translations []*models.Translation) error {
+	storageKey := s.buildStorageKey(input.User.ID, ownerID, "source", input.Filename)
 	if err := s.storage.Save(ctx, storageKey, bytes.NewReader(input.Data), input.ContentType); err != nil {
-		return nil, err
+		return err
 	}
-	_, err = s.files.Create(ctx, &models.FileRecord{
-		TranslationID: translationID,
-		StorageKey:    storageKey,
-		Kind:          models.FileKindSource,
-		StoredUntil:   deleteAfter,
-	})
-	if err != nil {
-		s.logger.Error().Err(err).Msg("failed to store file record")
+	for _, translationEntity := range translations {
+		_, err := s.files.Create(ctx, &models.FileRecord{
+			TranslationID: translationEntity.ID,
+			StorageKey:    storageKey,
+			Kind:          models.FileKindSource,
+			StoredUntil:   draft.deleteAfter,
+		})
+		if err != nil {
+			s.logger.Error().Err(err).Msg("failed to store file record")
+		}
 	}
-	if deleteAfter != nil {
-		delay := time.Until(*deleteAfter)
+	if draft.deleteAfter != nil {
+		delay := time.Until(*draft.deleteAfter)
 		if delay > 0 {
 			if _, qerr := s.queue.EnqueueCleanup(queue.CleanupPayload{StorageKey: storageKey}, delay); qerr != nil {
 				s.logger.Warn().Err(qerr).Msg("failed to enqueue source cleanup")
 			}
 		}
 	}
+	return nil
+}
 
-	s.logger.Info().Str("translation_id", translationID).Str("model", input.ModelKey).Int("characters", characterCount).Msg("translation created")
+// uniqueLanguages drops empty and repeated language codes.
+func uniqueLanguages(codes []string) []string {
+	seen := make(map[string]bool, len(codes))
+	result := make([]string, 0, len(codes))
+	for _, code := range codes {
+		code = strings.TrimSpace(code)
+		key := strings.ToUpper(code)
+		if code == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, code)
+	}
+	return result
+}
 
-	return &CreateTranslationResult{Translation: translationEntity, Model: *model}, nil
+// detectionWarnings reports confident detections that contradict the
+// selected source language or that the model cannot translate from.
+func (s *TranslationService) detectionWarnings(ctx context.Context, model translation.Model, sourceLang string, detection translation.Detection) []string {
+	if detection.Language == "" || detection.Confidence < detectionWarnConfidence {
+		return nil
+	}
+	if sourceLang != "" {
+		if translation.BaseLanguage(sourceLang) != translation.BaseLanguage(detection.Language) {
+			return []string{fmt.Sprintf("the document appears to be in %s (confidence %.0f%%), but %s was selected as source language", detection.Language, detection.Confidence*100, strings.ToUpper(sourceLang))}
+		}
+		return nil
+	}
+	if !s.languages.SupportsSource(ctx, model, detection.Language) {
+		return []string{fmt.Sprintf("the document appears to be in %s, which %s cannot translate from", detection.Language, model.DisplayName)}
+	}
+	return nil
 }
 
 // QueueTranslation enqueues actual translation task after payment confirmation.
@@ -188,7 +513,15 @@ func (s *TranslationService) QueueTranslation(ctx context.Context, translationID
 		Options:       map[string]interface{}(translationEntity.Options),
 	}
 
-	taskInfo, err := s.queue.EnqueueTranslation(payload)
+	// The customer has already paid at this point, so instead of refusing
+	// work that exceeds the provider quota we defer it until usage is re-polled.
+	var delay time.Duration
+	if qerr := s.quota.Admit(model.Provider, translationEntity.CharacterCount); qerr != nil {
+		delay = s.quota.RetryDelay()
+		s.logger.Warn().Err(qerr).Str("translation_id", translationID).Dur("delay", delay).Msg("translation deferred")
+	}
+
+	taskInfo, err := s.queue.EnqueueTranslationIn(payload, delay)
 	if err != nil {
 		return err
 	}
@@ -199,6 +532,108 @@ func (s *TranslationService) QueueTranslation(ctx context.Context, translationID
 	return nil
 }
 
+// QueueOrder enqueues every pending translation of a paid order.
+func (s *TranslationService) QueueOrder(ctx context.Context, orderID string) error {
+	children, err := s.translations.ListByOrder(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	if len(children) == 0 {
+		return fmt.Errorf("order %s has no translations", orderID)
+	}
+	var errs []error
+	for _, child := range children {
+		// Webhooks may be delivered more than once.
+		if child.Status != models.TranslationPending {
+			continue
+		}
+		if err := s.QueueTranslation(ctx, child.ID); err != nil {
+			errs = append(errs, fmt.Errorf("queue %s: %w", child.TargetLang, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// OrderSummary is an order with its translations and aggregate progress.
+type OrderSummary struct {
+	*models.TranslationOrder
+	Status       models.OrderStatus   `json:"status"`
+	Progress     float64              `json:"progress"`
+	Total        int                  `json:"total"`
+	Completed    int                  `json:"completed"`
+	Failed       int                  `json:"failed"`
+	Translations []models.Translation `json:"translations"`
+}
+
+// GetOrder returns the order with the state of its translations.
+func (s *TranslationService) GetOrder(ctx context.Context, id string) (*OrderSummary, error) {
+	order, err := s.orders.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	children, err := s.translations.ListByOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	summary := &OrderSummary{TranslationOrder: order, Total: len(children), Translations: children}
+	pending := 0
+	for _, child := range children {
+		switch child.Status {
+		case models.TranslationCompleted:
+			summary.Completed++
+		case models.TranslationFailed:
+			summary.Failed++
+		case models.TranslationPending:
+			pending++
+		}
+	}
+	switch finished := summary.Completed + summary.Failed; {
+	case summary.Total == 0 || pending == summary.Total:
+		summary.Status = models.OrderPending
+	case summary.Completed == summary.Total:
+		summary.Status = models.OrderCompleted
+	case finished == summary.Total && summary.Completed == 0:
+		summary.Status = models.OrderFailed
+	case finished == summary.Total:
+		summary.Status = models.OrderPartiallyCompleted
+	default:
+		summary.Status = models.OrderProcessing
+	}
+	if summary.Total > 0 {
+		summary.Progress = math.Round(float64(summary.Completed+summary.Failed)/float64(summary.Total)*100) / 100
+	}
+	return summary, nil
+}
+
+// EnsureOrderCapacity refuses orders whose combined volume exceeds the
+// provider quota.
+func (s *TranslationService) EnsureOrderCapacity(ctx context.Context, order *models.TranslationOrder) error {
+	model := translation.GetModelByKey(order.ModelKey)
+	if model == nil {
+		return fmt.Errorf("model %s not found", order.ModelKey)
+	}
+	children, err := s.translations.ListByOrder(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+	characters := 0
+	for _, child := range children {
+		characters += child.CharacterCount
+	}
+	return s.quota.Admit(model.Provider, characters)
+}
+
+// EnsureCapacity refuses translations whose provider quota is exhausted.
+// It is checked again right before checkout so customers are not charged
+// for work that cannot run.
+func (s *TranslationService) EnsureCapacity(translationEntity *models.Translation) error {
+	model := translation.GetModelByKey(translationEntity.ModelKey)
+	if model == nil {
+		return fmt.Errorf("model %s not found", translationEntity.ModelKey)
+	}
+	return s.quota.Admit(model.Provider, translationEntity.CharacterCount)
+}
+
 // CompleteTranslation is invoked by worker after translation finishes successfully.
 func (s *TranslationService) CompleteTranslation(ctx context.Context, translationID string, translatedData []byte, contentType string, filename string) error {
 	translationEntity, err := s.translations.GetByID(ctx, translationID)
@@ -272,11 +707,167 @@ func DetermineContentType(filename string) string {
 		return "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
 	case ".epub":
 		return "application/epub+zip"
+	case ".xlf", ".xliff":
+		return "application/x-xliff+xml"
+	case ".json", ".xcstrings":
+		return "application/json"
+	case ".xml":
+		return "application/xml"
+	case ".po", ".pot":
+		return "text/x-gettext-translation"
+	case ".yaml", ".yml":
+		return "application/yaml"
+	case ".html", ".htm":
+		return "text/html"
+	case ".xhtml":
+		return "application/xhtml+xml"
+	case ".md", ".markdown":
+		return "text/markdown"
+	case ".srt":
+		return "application/x-subrip"
+	case ".vtt":
+		return "text/vtt"
 	default:
 		return "text/plain"
 	}
 }
 
+// ExportTMX writes the aligned segment pairs of the user's completed
+// translations as TMX. sourceLang and targetLang, when set, restrict the
+// export to that language pair. Translations whose files were deleted or
+// whose segments do not line up are left out.
+func (s *TranslationService) ExportTMX(ctx context.Context, userID, sourceLang, targetLang string, w io.Writer) error {
+	translations, err := s.translations.ListCompletedByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	sourceLang = translation.NormalizeLanguageCode(sourceLang)
+	targetLang = translation.NormalizeLanguageCode(targetLang)
+	writer := translation.NewTMXWriter(w, sourceLang)
+	seen := make(map[string]bool)
+	for i := range translations {
+		t := &translations[i]
+		unitSource := translation.NormalizeLanguageCode(t.EffectiveSourceLang())
+		unitTarget := translation.NormalizeLanguageCode(t.TargetLang)
+		if unitSource == "" || !translation.CanReassemble(t.OriginalFilename) ||
+			(sourceLang != "" && unitSource != sourceLang) || (targetLang != "" && unitTarget != targetLang) {
+			continue
+		}
+		sources, targets, err := s.alignedSegments(ctx, t)
+		if err != nil {
+			s.logger.Warn().Err(err).Str("translation_id", t.ID).Msg("skipping translation in tmx export")
+			continue
+		}
+		for j, source := range sources {
+			source = translation.NormalizeSegment(source)
+			target := strings.TrimSpace(targets[j])
+			key := unitSource + ">" + unitTarget + ":" + translation.SegmentHash(source)
+			if source == "" || target == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			if err := writer.Write(translation.TMXUnit{SourceLang: unitSource, TargetLang: unitTarget, Source: source, Target: target}); err != nil {
+				return err
+			}
+		}
+	}
+	return writer.Close()
+}
+
+// alignedSegments segments the source and final translated files of t,
+// which must produce the same number of segments.
+func (s *TranslationService) alignedSegments(ctx context.Context, t *models.Translation) ([]string, []string, error) {
+	source, err := s.readFile(ctx, t.ID, models.FileKindSource)
+	if err != nil {
+		return nil, nil, err
+	}
+	translated, err := s.readFile(ctx, t.ID, models.FileKindReviewed, models.FileKindTranslated)
+	if err != nil {
+		return nil, nil, err
+	}
+	sources, err := translation.DocumentSegments(t.OriginalFilename, source)
+	if err != nil {
+		return nil, nil, err
+	}
+	targets, err := translation.TranslatedSegments(t.OriginalFilename, t.TargetLang, translated)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(sources) != len(targets) {
+		return nil, nil, fmt.Errorf("%d source segments but %d translated", len(sources), len(targets))
+	}
+	return sources, targets, nil
+}
+
+// readFile loads the newest stored file of kinds of a translation.
+func (s *TranslationService) readFile(ctx context.Context, translationID string, kinds ...models.FileKind) ([]byte, error) {
+	files, err := s.files.ListByTranslation(ctx, translationID)
+	if err != nil {
+		return nil, err
+	}
+	file := latestFile(files, kinds...)
+	if file == nil {
+		return nil, fmt.Errorf("%s file missing", kinds[0])
+	}
+	reader, err := s.storage.Get(ctx, file.StorageKey)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+// latestFile returns the newest file of any of kinds. Reviews and post-edits
+// add versions, so the newest one is the current document.
+func latestFile(files []models.FileRecord, kinds ...models.FileKind) *models.FileRecord {
+	var latest *models.FileRecord
+	for i := range files {
+		for _, kind := range kinds {
+			if files[i].Kind == kind && (latest == nil || files[i].CreatedAt.After(latest.CreatedAt)) {
+				latest = &files[i]
+			}
+		}
+	}
+	return latest
+}
+
+// storeVersion saves a new version of the translated document under kind.
+// Every version gets its own storage key so earlier ones stay intact.
+func (s *TranslationService) storeVersion(ctx context.Context, t *models.Translation, kind models.FileKind, data []byte) (*models.FileRecord, error) {
+	files, err := s.files.ListByTranslation(ctx, t.ID)
+	if err != nil {
+		return nil, err
+	}
+	version := 1
+	for _, f := range files {
+		if f.Kind == models.FileKindTranslated || f.Kind == models.FileKindReviewed {
+			version++
+		}
+	}
+	filename := *t.TranslatedFilename
+	storageKey := s.buildStorageKey(t.UserID, t.ID, fmt.Sprintf("%s/v%d", kind, version), filename)
+	if err := s.storage.Save(ctx, storageKey, bytes.NewReader(data), DetermineContentType(filename)); err != nil {
+		return nil, err
+	}
+	record, err := s.files.Create(ctx, &models.FileRecord{
+		TranslationID: t.ID,
+		StorageKey:    storageKey,
+		Kind:          kind,
+		StoredUntil:   t.DeleteAfter,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if t.DeleteAfter != nil {
+		if delay := time.Until(*t.DeleteAfter); delay > 0 {
+			if _, qerr := s.queue.EnqueueCleanup(queue.CleanupPayload{StorageKey: storageKey}, delay); qerr != nil {
+				s.logger.Warn().Err(qerr).Msgf("failed to enqueue %s cleanup", kind)
+			}
+		}
+	}
+	return record, nil
+}
+
 func (s *TranslationService) ListTranslations(ctx context.Context, userID string, limit, offset int) ([]models.Translation, error) {
 	return s.translations.ListByUser(ctx, userID, limit, offset)
 }
@@ -290,20 +881,32 @@ func (s *TranslationService) GenerateDownloadURL(ctx context.Context, translatio
 	if err != nil {
 		return "", err
 	}
-	var targetKey string
-	for _, f := range files {
-		if f.Kind == models.FileKindTranslated {
-			targetKey = f.StorageKey
-			break
-		}
-	}
-	if targetKey == "" {
+	file := latestFile(files, models.FileKindReviewed, models.FileKindTranslated)
+	if file == nil {
 		return "", errors.New("translated file not ready")
 	}
-	return s.storage.SignedURL(ctx, targetKey, int(expiry.Seconds()))
+	return s.storage.SignedURL(ctx, file.StorageKey, int(expiry.Seconds()))
 }
 
-func (s *TranslationService) OpenTranslatedFile(ctx context.Context, translationID string) (io.ReadCloser, string, error) {
+// ErrUnsupportedFormat is returned for unknown download formats.
+var ErrUnsupportedFormat = errors.New("unsupported download format")
+
+// xliffVersion maps a download format to an XLIFF version.
+func xliffVersion(format string) (translation.XLIFFVersion, bool) {
+	switch strings.ToLower(format) {
+	case "xliff", "xliff12", "xliff-1.2":
+		return translation.XLIFF12, true
+	case "xliff2", "xliff20", "xliff-2.0":
+		return translation.XLIFF20, true
+	default:
+		return "", false
+	}
+}
+
+// OpenTranslatedFile opens the current document, including reviews and
+// post-edits. format "xliff" (1.2) or "xliff-2.0" returns the
+// segments as XLIFF for review instead.
+func (s *TranslationService) OpenTranslatedFile(ctx context.Context, translationID, format string) (io.ReadCloser, string, error) {
 	translationEntity, err := s.translations.GetByID(ctx, translationID)
 	if err != nil {
 		return nil, "", err
@@ -311,23 +914,112 @@ func (s *TranslationService) OpenTranslatedFile(ctx context.Context, translation
 	if translationEntity.TranslatedFilename == nil {
 		return nil, "", errors.New("translation not ready")
 	}
+	if format != "" && format != "original" {
+		version, ok := xliffVersion(format)
+		if !ok {
+			return nil, "", fmt.Errorf("%w %q", ErrUnsupportedFormat, format)
+		}
+		return s.exportXLIFF(ctx, translationEntity, version)
+	}
 	files, err := s.files.ListByTranslation(ctx, translationID)
 	if err != nil {
 		return nil, "", err
 	}
-	var targetKey string
-	for _, f := range files {
-		if f.Kind == models.FileKindTranslated {
-			targetKey = f.StorageKey
-			break
-		}
-	}
-	if targetKey == "" {
+	file := latestFile(files, models.FileKindReviewed, models.FileKindTranslated)
+	if file == nil {
 		return nil, "", errors.New("translated file missing")
 	}
-	reader, err := s.storage.Get(ctx, targetKey)
+	reader, err := s.storage.Get(ctx, file.StorageKey)
 	if err != nil {
 		return nil, "", err
 	}
 	return reader, *translationEntity.TranslatedFilename, nil
 }
+
+// exportXLIFF pairs the source segments with the current targets.
+func (s *TranslationService) exportXLIFF(ctx context.Context, t *models.Translation, version translation.XLIFFVersion) (io.ReadCloser, string, error) {
+	if !translation.CanReassemble(t.OriginalFilename) {
+		return nil, "", fmt.Errorf("%w: xliff is not available for %s files", ErrUnsupportedFormat, filepath.Ext(t.OriginalFilename))
+	}
+	sources, targets, err := s.alignedSegments(ctx, t)
+	if err != nil {
+		return nil, "", err
+	}
+	doc := translation.XLIFFDocument{
+		Version:    version,
+		Original:   t.OriginalFilename,
+		SourceLang: t.EffectiveSourceLang(),
+		TargetLang: t.TargetLang,
+	}
+	for i, source := range sources {
+		doc.Units = append(doc.Units, translation.XLIFFUnit{ID: strconv.Itoa(i + 1), Source: source, Target: targets[i]})
+	}
+	var buf bytes.Buffer
+	if err := translation.WriteXLIFF(&buf, doc); err != nil {
+		return nil, "", err
+	}
+	filename := strings.TrimSuffix(*t.TranslatedFilename, filepath.Ext(*t.TranslatedFilename)) + ".xlf"
+	return io.NopCloser(&buf), filename, nil
+}
+
+// ImportReviewedXLIFF writes the targets of a reviewed XLIFF into the
+// current document and stores it as the translation's reviewed file. Units
+// are matched to segments by id; empty targets keep the current text.
+func (s *TranslationService) ImportReviewedXLIFF(ctx context.Context, translationID string, r io.Reader) (*models.FileRecord, error) {
+	translationEntity, err := s.translations.GetByID(ctx, translationID)
+	if err != nil {
+		return nil, err
+	}
+	if translationEntity.Status != models.TranslationCompleted || translationEntity.TranslatedFilename == nil {
+		return nil, errors.New("translation not ready")
+	}
+	if !translation.CanReassemble(translationEntity.OriginalFilename) {
+		return nil, fmt.Errorf("reviewed xliff cannot be applied to %s files", filepath.Ext(translationEntity.OriginalFilename))
+	}
+	doc, err := translation.ReadXLIFF(r)
+	if err != nil {
+		return nil, err
+	}
+	source, err := s.readFile(ctx, translationID, models.FileKindSource)
+	if err != nil {
+		return nil, err
+	}
+	sources, targets, err := s.alignedSegments(ctx, translationEntity)
+	if err != nil {
+		return nil, err
+	}
+	if len(doc.Units) != len(sources) {
+		return nil, fmt.Errorf("xliff has %d units, the document has %d segments", len(doc.Units), len(sources))
+	}
+	for _, unit := range doc.Units {
+		idx, err := strconv.Atoi(unit.ID)
+		if err != nil || idx < 1 || idx > len(sources) {
+			return nil, fmt.Errorf("unknown xliff unit %q", unit.ID)
+		}
+		if translation.NormalizeSegment(unit.Source) != translation.NormalizeSegment(sources[idx-1]) {
+			return nil, fmt.Errorf("source of xliff unit %s does not match the document", unit.ID)
+		}
+		if strings.TrimSpace(unit.Target) != "" {
+			targets[idx-1] = unit.Target
+		}
+	}
+	current, err := s.readFile(ctx, translationID, models.FileKindReviewed, models.FileKindTranslated)
+	if err != nil {
+		return nil, err
+	}
+	reviewed, err := translation.ReviseDocument(ctx, translationEntity.OriginalFilename, translationEntity.TargetLang, source, current, targets)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := s.storeVersion(ctx, translationEntity, models.FileKindReviewed, reviewed)
+	if err != nil {
+		return nil, err
+	}
+	// Reviewed targets supersede the machine translation in the memory.
+	if err := s.memory.Store(ctx, translationEntity, sources, targets); err != nil {
+		s.logger.Warn().Err(err).Str("translation_id", translationID).Msg("failed to update translation memory")
+	}
+	s.logger.Info().Str("translation_id", translationID).Int("units", len(doc.Units)).Msg("reviewed translation imported")
+	return record, nil
+}
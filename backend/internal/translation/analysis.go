@@ -0,0 +1,287 @@
+package translation
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// MatchBand classifies a segment in a translation memory analysis.
+type MatchBand string
+
+const (
+	MatchRepetition MatchBand = "repetitions"
+	MatchExact      MatchBand = "100%"
+	MatchFuzzy95    MatchBand = "95-99%"
+	MatchFuzzy85    MatchBand = "85-94%"
+	MatchFuzzy75    MatchBand = "75-84%"
+	MatchNew        MatchBand = "no_match"
+)
+
+// matchBands lists the bands in report order.
+var matchBands = []MatchBand{MatchRepetition, MatchExact, MatchFuzzy95, MatchFuzzy85, MatchFuzzy75, MatchNew}
+
+// fuzzyCandidates bounds how many memory segments sharing words with a
+// segment are compared by edit distance.
+const fuzzyCandidates = 10
+
+// MatchWeights is the share of the full price charged per band. Bands
+// without a weight are charged in full.
+type MatchWeights map[MatchBand]float64
+
+// BandAnalysis sums up the segments of one band.
+type BandAnalysis struct {
+	Band       MatchBand `json:"band"`
+	Segments   int       `json:"segments"`
+	Words      int       `json:"words"`
+	Characters int       `json:"characters"`
+	Weight     float64   `json:"weight"`
+}
+
+// Analysis is a CAT-style breakdown of a document against a translation
+// memory.
+type Analysis struct {
+	Bands              []BandAnalysis `json:"bands"`
+	Segments           int            `json:"segments"`
+	Words              int            `json:"words"`
+	Characters         int            `json:"characters"`
+	WeightedCharacters int            `json:"weightedCharacters"`
+}
+
+// MatchedCharacters returns the characters outside the no-match band.
+func (a Analysis) MatchedCharacters() int {
+	matched := 0
+	for _, band := range a.Bands {
+		if band.Band != MatchNew {
+			matched += band.Characters
+		}
+	}
+	return matched
+}
+
+// BillableCharacters charges characterCount in full except for matched
+// segments, which are charged at their band weight.
+func (a Analysis) BillableCharacters(characterCount int) int {
+	matched, weighted := 0, 0
+	for _, band := range a.Bands {
+		if band.Band != MatchNew {
+			matched += band.Characters
+			weighted += int(math.Round(float64(band.Characters) * band.Weight))
+		}
+	}
+	if matched > characterCount {
+		return weighted * characterCount / matched
+	}
+	return characterCount - matched + weighted
+}
+
+// AnalyzeSegments classifies every segment: repeats of an earlier segment,
+// exact memory matches, fuzzy matches by edit distance against candidates,
+// and new text. exact holds normalized sources with a stored translation.
+func AnalyzeSegments(segments []string, exact map[string]string, candidates []string, weights MatchWeights) Analysis {
+	bands := make(map[MatchBand]*BandAnalysis, len(matchBands))
+	for _, band := range matchBands {
+		weight, ok := weights[band]
+		if !ok {
+			weight = 1
+		}
+		bands[band] = &BandAnalysis{Band: band, Weight: weight}
+	}
+	index := newFuzzyIndex(candidates)
+	seen := make(map[string]bool, len(segments))
+	analysis := Analysis{}
+	for _, segment := range segments {
+		normalized := NormalizeSegment(segment)
+		if normalized == "" {
+			continue
+		}
+		var band MatchBand
+		switch _, stored := exact[normalized]; {
+		case seen[normalized]:
+			band = MatchRepetition
+		case stored:
+			band = MatchExact
+		default:
+			band = fuzzyBand(index.bestSimilarity(normalized))
+		}
+		seen[normalized] = true
+
+		words := len(strings.Fields(normalized))
+		characters := utf8.RuneCountInString(normalized)
+		stats := bands[band]
+		stats.Segments++
+		stats.Words += words
+		stats.Characters += characters
+		analysis.Segments++
+		analysis.Words += words
+		analysis.Characters += characters
+	}
+	for _, band := range matchBands {
+		stats := bands[band]
+		analysis.Bands = append(analysis.Bands, *stats)
+		analysis.WeightedCharacters += int(math.Round(float64(stats.Characters) * stats.Weight))
+	}
+	return analysis
+}
+
+func fuzzyBand(similarity float64) MatchBand {
+	switch percent := int(math.Floor(similarity * 100)); {
+	case percent >= 95:
+		return MatchFuzzy95
+	case percent >= 85:
+		return MatchFuzzy85
+	case percent >= 75:
+		return MatchFuzzy75
+	default:
+		return MatchNew
+	}
+}
+
+// fuzzyIndex finds memory segments sharing words with a segment, so that
+// only likely matches are compared by edit distance.
+type fuzzyIndex struct {
+	segments [][]rune
+	words    map[string][]int
+}
+
+func newFuzzyIndex(candidates []string) *fuzzyIndex {
+	index := &fuzzyIndex{words: make(map[string][]int)}
+	for _, candidate := range candidates {
+		normalized := NormalizeSegment(candidate)
+		if normalized == "" {
+			continue
+		}
+		id := len(index.segments)
+		index.segments = append(index.segments, []rune(normalized))
+		for _, word := range uniqueWords(normalized) {
+			index.words[word] = append(index.words[word], id)
+		}
+	}
+	return index
+}
+
+// bestSimilarity returns the highest similarity of segment to any
+// candidate, or 0.
+func (x *fuzzyIndex) bestSimilarity(segment string) float64 {
+	words := uniqueWords(segment)
+	if len(words) == 0 || len(x.segments) == 0 {
+		return 0
+	}
+	overlap := make(map[int]int)
+	for _, word := range words {
+		for _, id := range x.words[word] {
+			overlap[id]++
+		}
+	}
+	ids := make([]int, 0, len(overlap))
+	for id, shared := range overlap {
+		// A 75% match cannot share fewer than half of the words.
+		if shared*2 >= len(words) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if overlap[ids[i]] != overlap[ids[j]] {
+			return overlap[ids[i]] > overlap[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+	if len(ids) > fuzzyCandidates {
+		ids = ids[:fuzzyCandidates]
+	}
+	runes := []rune(segment)
+	best := 0.0
+	for _, id := range ids {
+		if similarity := runeSimilarity(runes, x.segments[id], best); similarity > best {
+			best = similarity
+		}
+	}
+	return best
+}
+
+// Similarity returns 1 minus the edit distance of the normalized segments
+// relative to the longer one.
+func Similarity(a, b string) float64 {
+	return runeSimilarity([]rune(NormalizeSegment(a)), []rune(NormalizeSegment(b)), 0)
+}
+
+// runeSimilarity computes the similarity of a and b, giving up with 0 once
+// it cannot exceed floor.
+func runeSimilarity(a, b []rune, floor float64) float64 {
+	longest := len(a)
+	if len(b) > longest {
+		longest = len(b)
+	}
+	if longest == 0 {
+		return 1
+	}
+	maxDistance := int(math.Floor((1 - floor) * float64(longest)))
+	distance, ok := boundedLevenshtein(a, b, maxDistance)
+	if !ok {
+		return 0
+	}
+	return 1 - float64(distance)/float64(longest)
+}
+
+// boundedLevenshtein returns the edit distance of a and b, or false when it
+// exceeds maxDistance.
+func boundedLevenshtein(a, b []rune, maxDistance int) (int, bool) {
+	diff := len(a) - len(b)
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff > maxDistance {
+		return 0, false
+	}
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min3(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if curr[j] < rowMin {
+				rowMin = curr[j]
+			}
+		}
+		if rowMin > maxDistance {
+			return 0, false
+		}
+		prev, curr = curr, prev
+	}
+	if prev[len(b)] > maxDistance {
+		return 0, false
+	}
+	return prev[len(b)], true
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+func uniqueWords(segment string) []string {
+	fields := strings.Fields(strings.ToLower(segment))
+	seen := make(map[string]bool, len(fields))
+	words := fields[:0]
+	for _, word := range fields {
+		if !seen[word] {
+			seen[word] = true
+			words = append(words, word)
+		}
+	}
+	return words
+}
@@ -0,0 +1,59 @@
+package translation
+
+import "testing"
+
+func TestAnalyzeSegmentsBands(t *testing.T) {
+	segments := []string{
+		"The quick brown fox jumps over the lazy dog.",
+		"The quick brown fox jumps over the lazy dogs.",
+		"Completely new sentence here.",
+		"The quick brown fox jumps over the lazy dog.",
+	}
+	exact := map[string]string{segments[0]: "Der schnelle braune Fuchs springt über den faulen Hund."}
+	weights := MatchWeights{MatchRepetition: 0.1, MatchExact: 0.1, MatchFuzzy95: 0.3}
+
+	analysis := AnalyzeSegments(segments, exact, []string{segments[0]}, weights)
+	got := map[MatchBand]int{}
+	for _, band := range analysis.Bands {
+		got[band.Band] = band.Segments
+	}
+	want := map[MatchBand]int{MatchRepetition: 1, MatchExact: 1, MatchFuzzy95: 1, MatchNew: 1}
+	for band, segments := range want {
+		if got[band] != segments {
+			t.Errorf("band %s: expected %d segments, got %d", band, segments, got[band])
+		}
+	}
+	if analysis.Segments != 4 || len(analysis.Bands) != len(matchBands) {
+		t.Fatalf("unexpected totals: %+v", analysis)
+	}
+	if analysis.MatchedCharacters() != 44*3+1 {
+		t.Fatalf("expected %d matched characters, got %d", 44*3+1, analysis.MatchedCharacters())
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	if got := Similarity("abcd", "abcd"); got != 1 {
+		t.Fatalf("expected identical segments to match fully, got %f", got)
+	}
+	if got := Similarity("abcd", "abce"); got != 0.75 {
+		t.Fatalf("expected 0.75, got %f", got)
+	}
+	if got := Similarity("", "abc"); got != 0 {
+		t.Fatalf("expected 0, got %f", got)
+	}
+}
+
+func TestBillableCharacters(t *testing.T) {
+	analysis := Analysis{Bands: []BandAnalysis{
+		{Band: MatchExact, Characters: 100, Weight: 0.1},
+		{Band: MatchFuzzy85, Characters: 100, Weight: 0.6},
+		{Band: MatchNew, Characters: 300, Weight: 1},
+	}}
+	if got := analysis.BillableCharacters(500); got != 370 {
+		t.Fatalf("expected 370 billable characters, got %d", got)
+	}
+	// Matches counted on more text than billed are scaled down.
+	if got := analysis.BillableCharacters(100); got != 35 {
+		t.Fatalf("expected 35 billable characters, got %d", got)
+	}
+}
@@ -0,0 +1,190 @@
+package translation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+)
+
+// catalogUnit is a path to a stringUnit in an Xcode string catalog
+// localization: empty for the plain value, or variation kind and case pairs
+// such as ["plural", "one"].
+type catalogUnit struct {
+	path  []string
+	value string
+}
+
+// rewriteStringCatalog translates an Xcode .xcstrings catalog from its
+// sourceLanguage into the targetLang localization of every key, plural and
+// device variations included. Keys marked shouldTranslate false are kept.
+func rewriteStringCatalog(data []byte, targetLang string, replace func(string) string) ([]byte, error) {
+	catalog, entries, err := decodeStringCatalog(data)
+	if err != nil {
+		return nil, err
+	}
+	sourceLang, _ := catalog["sourceLanguage"].(string)
+	lang := xcodeLang(targetLang)
+	for _, key := range sortedKeys(entries) {
+		entry, _ := entries[key].(map[string]interface{})
+		if entry == nil {
+			entry = map[string]interface{}{}
+			entries[key] = entry
+		}
+		if translate, ok := entry["shouldTranslate"].(bool); ok && !translate {
+			continue
+		}
+		units := sourceUnits(key, entry, sourceLang)
+		if len(units) == 0 {
+			continue
+		}
+		localizations, _ := entry["localizations"].(map[string]interface{})
+		if localizations == nil {
+			localizations = map[string]interface{}{}
+		}
+		target, _ := localizations[lang].(map[string]interface{})
+		if target == nil {
+			target = map[string]interface{}{}
+		}
+		for _, unit := range units {
+			node := target
+			for i := 0; i+1 < len(unit.path); i += 2 {
+				node = childMap(childMap(childMap(node, "variations"), unit.path[i]), unit.path[i+1])
+			}
+			node["stringUnit"] = map[string]interface{}{
+				"state": "translated",
+				"value": replaceTrimmed(unit.value, replace),
+			}
+		}
+		if lang != "" && lang != sourceLang {
+			localizations[lang] = target
+			entry["localizations"] = localizations
+		}
+	}
+	if lang == "" || lang == sourceLang {
+		return data, nil
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(catalog); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// stringCatalogTargets lists the targetLang values of a string catalog
+// aligned with the source segments rewriteStringCatalog reads.
+func stringCatalogTargets(data []byte, targetLang string) ([]string, error) {
+	catalog, entries, err := decodeStringCatalog(data)
+	if err != nil {
+		return nil, err
+	}
+	sourceLang, _ := catalog["sourceLanguage"].(string)
+	lang := xcodeLang(targetLang)
+	var targets []string
+	for _, key := range sortedKeys(entries) {
+		entry, _ := entries[key].(map[string]interface{})
+		if translate, ok := entry["shouldTranslate"].(bool); ok && !translate {
+			continue
+		}
+		localizations, _ := entry["localizations"].(map[string]interface{})
+		target, _ := localizations[lang].(map[string]interface{})
+		for _, unit := range sourceUnits(key, entry, sourceLang) {
+			node := target
+			for i := 0; i+1 < len(unit.path) && node != nil; i += 2 {
+				variations, _ := node["variations"].(map[string]interface{})
+				kind, _ := variations[unit.path[i]].(map[string]interface{})
+				node, _ = kind[unit.path[i+1]].(map[string]interface{})
+			}
+			stringUnit, _ := node["stringUnit"].(map[string]interface{})
+			value, _ := stringUnit["value"].(string)
+			targets = append(targets, strings.TrimSpace(value))
+		}
+	}
+	return targets, nil
+}
+
+func decodeStringCatalog(data []byte) (map[string]interface{}, map[string]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var catalog map[string]interface{}
+	if err := decoder.Decode(&catalog); err != nil {
+		return nil, nil, err
+	}
+	entries, ok := catalog["strings"].(map[string]interface{})
+	if !ok {
+		return nil, nil, errors.New("invalid string catalog: missing strings")
+	}
+	return catalog, entries, nil
+}
+
+// sourceUnits lists the source strings of a catalog entry. A key without a
+// source localization is its own source text.
+func sourceUnits(key string, entry map[string]interface{}, sourceLang string) []catalogUnit {
+	localizations, _ := entry["localizations"].(map[string]interface{})
+	source, ok := localizations[sourceLang].(map[string]interface{})
+	if !ok {
+		if strings.TrimSpace(key) == "" {
+			return nil
+		}
+		return []catalogUnit{{value: key}}
+	}
+	var units []catalogUnit
+	var walk func(node map[string]interface{}, path []string)
+	walk = func(node map[string]interface{}, path []string) {
+		if stringUnit, ok := node["stringUnit"].(map[string]interface{}); ok {
+			if value, _ := stringUnit["value"].(string); strings.TrimSpace(value) != "" {
+				units = append(units, catalogUnit{path: path, value: value})
+			}
+		}
+		variations, _ := node["variations"].(map[string]interface{})
+		for _, kind := range sortedKeys(variations) {
+			cases, _ := variations[kind].(map[string]interface{})
+			for _, name := range sortedKeys(cases) {
+				if child, ok := cases[name].(map[string]interface{}); ok {
+					walk(child, append(append([]string{}, path...), kind, name))
+				}
+			}
+		}
+	}
+	walk(source, nil)
+	return units
+}
+
+func childMap(node map[string]interface{}, key string) map[string]interface{} {
+	child, ok := node[key].(map[string]interface{})
+	if !ok {
+		child = map[string]interface{}{}
+		node[key] = child
+	}
+	return child
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// xcodeLang converts a language code such as "PT-BR" or "ZH-HANS" to the
+// form Xcode uses: "pt-BR", "zh-Hans".
+func xcodeLang(lang string) string {
+	parts := strings.Split(strings.ReplaceAll(lang, "_", "-"), "-")
+	for i, part := range parts {
+		switch {
+		case i == 0:
+			parts[i] = strings.ToLower(part)
+		case len(part) == 4:
+			parts[i] = strings.ToUpper(part[:1]) + strings.ToLower(part[1:])
+		default:
+			parts[i] = strings.ToUpper(part)
+		}
+	}
+	return strings.Join(parts, "-")
+}
@@ -0,0 +1,100 @@
+package translation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// SegmentBatch is a chunk of consecutive segments plus the segments that
+// precede it, so text-level providers can keep terminology and tone consistent.
+type SegmentBatch struct {
+	Segments        []string
+	PrecedingSource []string
+	PrecedingTarget []string
+}
+
+// BatchTranslateFunc translates one batch and returns a target per segment.
+type BatchTranslateFunc func(ctx context.Context, batch SegmentBatch) ([]string, error)
+
+// ChunkSegments groups segment indexes so that each chunk stays within
+// maxChars. A single oversized segment forms its own chunk.
+func ChunkSegments(segments []string, maxChars int) [][]int {
+	var chunks [][]int
+	var current []int
+	size := 0
+	for i, seg := range segments {
+		n := utf8.RuneCountInString(seg)
+		if len(current) > 0 && maxChars > 0 && size+n > maxChars {
+			chunks = append(chunks, current)
+			current = nil
+			size = 0
+		}
+		current = append(current, i)
+		size += n
+	}
+	if len(current) > 0 {
+		chunks = append(chunks, current)
+	}
+	return chunks
+}
+
+// TranslateSegments translates segments chunk by chunk. Each batch receives
+// up to contextSegments preceding source/target pairs. Placeholders are
+// masked while the segments are at the provider and verified afterwards.
+func TranslateSegments(ctx context.Context, segments []string, maxChars, contextSegments int, fn BatchTranslateFunc) ([]string, error) {
+	segments, masks := MaskSegments(segments)
+	targets := make([]string, len(segments))
+	for _, chunk := range ChunkSegments(segments, maxChars) {
+		batch := SegmentBatch{Segments: make([]string, len(chunk))}
+		for i, idx := range chunk {
+			batch.Segments[i] = segments[idx]
+		}
+		start := chunk[0] - contextSegments
+		if start < 0 {
+			start = 0
+		}
+		batch.PrecedingSource = segments[start:chunk[0]]
+		batch.PrecedingTarget = targets[start:chunk[0]]
+
+		translated, err := fn(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+		if len(translated) != len(chunk) {
+			return nil, fmt.Errorf("expected %d translated segments, got %d", len(chunk), len(translated))
+		}
+		for i, idx := range chunk {
+			targets[idx] = translated[i]
+		}
+	}
+	return RestoreSegments(masks, targets)
+}
+
+// TranslateLines translates text line by line, keeping blank lines and the
+// surrounding whitespace of each line untouched.
+func TranslateLines(ctx context.Context, text string, maxChars, contextSegments int, fn BatchTranslateFunc) (string, error) {
+	lines := strings.Split(text, "\n")
+	var segments []string
+	var positions []int
+	for i, line := range lines {
+		if trimmed := strings.TrimSpace(line); trimmed != "" {
+			segments = append(segments, trimmed)
+			positions = append(positions, i)
+		}
+	}
+	if len(segments) == 0 {
+		return text, nil
+	}
+	targets, err := TranslateSegments(ctx, segments, maxChars, contextSegments, fn)
+	if err != nil {
+		return "", err
+	}
+	for i, pos := range positions {
+		line := lines[pos]
+		start := strings.Index(line, segments[i])
+		lines[pos] = line[:start] + targets[i] + line[start+len(segments[i]):]
+	}
+	return strings.Join(lines, "\n"), nil
+}
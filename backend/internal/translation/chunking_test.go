@@ -0,0 +1,53 @@
+package translation
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestChunkSegments(t *testing.T) {
+	chunks := ChunkSegments([]string{"aaaa", "bbb", "cc", "dddddddd", "e"}, 7)
+	want := [][]int{{0, 1}, {2}, {3}, {4}}
+	if !reflect.DeepEqual(chunks, want) {
+		t.Fatalf("expected %v got %v", want, chunks)
+	}
+}
+
+func TestTranslateLinesKeepsLayoutAndContext(t *testing.T) {
+	var batches []SegmentBatch
+	fn := func(_ context.Context, batch SegmentBatch) ([]string, error) {
+		batches = append(batches, batch)
+		out := make([]string, len(batch.Segments))
+		for i, seg := range batch.Segments {
+			out[i] = strings.ToUpper(seg)
+		}
+		return out, nil
+	}
+
+	got, err := TranslateLines(context.Background(), "one\n\n  two  \nthree\n", 6, 1, fn)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "ONE\n\n  TWO  \nTHREE\n" {
+		t.Fatalf("unexpected output %q", got)
+	}
+	if len(batches) != 2 {
+		t.Fatalf("expected 2 batches got %d", len(batches))
+	}
+	last := batches[1]
+	if !reflect.DeepEqual(last.PrecedingSource, []string{"two"}) || !reflect.DeepEqual(last.PrecedingTarget, []string{"TWO"}) {
+		t.Fatalf("unexpected context %+v", last)
+	}
+}
+
+func TestParseMarkedSegments(t *testing.T) {
+	targets, ok := parseMarkedSegments("<<2>> zwei\ndrei\n<<1>> eins\n", 2)
+	if !ok || !reflect.DeepEqual(targets, []string{"eins", "zwei\ndrei"}) {
+		t.Fatalf("unexpected parse %v %v", targets, ok)
+	}
+	if _, ok := parseMarkedSegments("<<1>> eins", 2); ok {
+		t.Fatal("expected missing marker to fail")
+	}
+}
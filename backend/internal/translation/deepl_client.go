@@ -21,6 +21,10 @@ const (
 	deepLAPIVersion   = "/v2"
 	deepLPollInterval = 3 * time.Second
 	deepLPollTimeout  = 5 * time.Minute
+	// deepLTextChunkChars keeps text requests well below DeepL's 128 KiB
+	// request limit.
+	deepLTextChunkChars = 30000
+	deepLMaxTexts       = 50
 )
 
 // DeepLClient interacts with DeepL API.
@@ -28,11 +32,13 @@ type DeepLClient struct {
 	httpClient *http.Client
 	apiKey     string
 	baseURL    string
+	retry      RetryPolicy
 }
 
 // DeepLOptions configure DeepL client.
 type DeepLOptions struct {
 	UseFreeAPI bool
+	Retry      RetryPolicy
 }
 
 // NewDeepLClient constructs a DeepL client.
@@ -45,6 +51,7 @@ func NewDeepLClient(apiKey string, opts DeepLOptions) *DeepLClient {
 		httpClient: &http.Client{Timeout: 60 * time.Second},
 		apiKey:     apiKey,
 		baseURL:    base,
+		retry:      opts.Retry,
 	}
 }
 
@@ -59,17 +66,45 @@ type DocumentOptions struct {
 	FileName         string
 }
 
-// DeepLError represents API errors.
+// DeepLError represents API errors. It unwraps to a typed provider error
+// such as ErrRateLimited.
 type DeepLError struct {
-	Message string `json:"message"`
+	StatusCode int           `json:"-"`
+	Message    string        `json:"message"`
+	RetryAfter time.Duration `json:"-"`
 }
 
 func (e *DeepLError) Error() string {
 	return fmt.Sprintf("deepl api error: %s", e.Message)
 }
 
+// HTTPStatus returns the response status that produced the error.
+func (e *DeepLError) HTTPStatus() int {
+	return e.StatusCode
+}
+
+// Unwrap returns the typed provider error for the status.
+func (e *DeepLError) Unwrap() error {
+	return statusError(e.StatusCode)
+}
+
+// DeepLDocument is the handle of an uploaded document.
+type DeepLDocument struct {
+	DocumentID  string `json:"document_id"`
+	DocumentKey string `json:"document_key"`
+}
+
 // TranslateDocument submits a document and returns translated bytes.
 func (c *DeepLClient) TranslateDocument(ctx context.Context, reader io.Reader, opts DocumentOptions) ([]byte, error) {
+	doc, err := c.SubmitDocument(ctx, reader, opts)
+	if err != nil {
+		return nil, err
+	}
+	return c.WaitDocument(ctx, *doc)
+}
+
+// SubmitDocument uploads a document for translation. DeepL bills on upload.
+func (c *DeepLClient) SubmitDocument(ctx context.Context, reader io.Reader, opts DocumentOptions) (*DeepLDocument, error) {
 	uploadURL := c.makeURL("document")
 
 	body := &bytes.Buffer{}
@@ -83,7 +118,7 @@ func (c *DeepLClient) TranslateDocument(ctx context.Context, reader io.Reader, o
 		return nil, err
 	}
 
-	_ = writer.WriteField("target_lang", strings.ToUpper(opts.TargetLang))
+	_ = writer.WriteField("target_lang", strings.ToUpper(TargetVariant(opts.TargetLang)))
 	if opts.SourceLang != "" {
 		_ = writer.WriteField("source_lang", strings.ToUpper(opts.SourceLang))
 	}
@@ -111,25 +146,25 @@ func (c *DeepLClient) TranslateDocument(ctx context.Context, reader io.Reader, o
 	req.Header.Set("Content-Type", writer.FormDataContentType())
 	req.Header.Set("Authorization", fmt.Sprintf("DeepL-Auth-Key %s", c.apiKey))
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		return nil, parseDeepLError(resp.Body)
+		return nil, parseDeepLError(resp.StatusCode, resp.Header, resp.Body)
 	}
 
-	var uploaded struct {
-		DocumentID       string `json:"document_id"`
-		DocumentKey      string `json:"document_key"`
-		BilledCharacters int    `json:"billed_characters"`
-	}
+	var uploaded DeepLDocument
 	if err := json.NewDecoder(resp.Body).Decode(&uploaded); err != nil {
 		return nil, err
 	}
+	return &uploaded, nil
+}
 
+// WaitDocument polls an uploaded document and downloads the result.
+func (c *DeepLClient) WaitDocument(ctx context.Context, uploaded DeepLDocument) ([]byte, error) {
 	statusURL := c.makeURL(path.Join("document", uploaded.DocumentID))
 	resultURL := c.makeURL(path.Join("document", uploaded.DocumentID, "result"))
 
@@ -154,7 +189,7 @@ func (c *DeepLClient) TranslateDocument(ctx context.Context, reader io.Reader, o
 			statusReq.Header.Set("Authorization", fmt.Sprintf("DeepL-Auth-Key %s", c.apiKey))
 			statusReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-			statusResp, err := c.httpClient.Do(statusReq)
+			statusResp, err := sendWithRetry(c.httpClient, c.retry, statusReq, true)
 			if err != nil {
 				return nil, err
 			}
@@ -165,7 +200,7 @@ func (c *DeepLClient) TranslateDocument(ctx context.Context, reader io.Reader, o
 				return nil, err
 			}
 			if statusResp.StatusCode >= 400 {
-				return nil, parseDeepLError(bytes.NewReader(statusBody))
+				return nil, parseDeepLError(statusResp.StatusCode, statusResp.Header, bytes.NewReader(statusBody))
 			}
 
 			var statusResult struct {
@@ -188,22 +223,22 @@ func (c *DeepLClient) TranslateDocument(ctx context.Context, reader io.Reader, o
 				resultReq.Header.Set("Authorization", fmt.Sprintf("DeepL-Auth-Key %s", c.apiKey))
 				resultReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-				resultResp, err := c.httpClient.Do(resultReq)
+				resultResp, err := sendWithRetry(c.httpClient, c.retry, resultReq, true)
 				if err != nil {
 					return nil, err
 				}
 				defer resultResp.Body.Close()
 				if resultResp.StatusCode >= 400 {
-					return nil, parseDeepLError(resultResp.Body)
+					return nil, parseDeepLError(resultResp.StatusCode, resultResp.Header, resultResp.Body)
 				}
 				return io.ReadAll(resultResp.Body)
 			case "translating", "queued", "uploaded":
 				continue
 			case "error":
 				if statusResult.Message != "" {
-					return nil, errors.New(statusResult.Message)
+					return nil, fmt.Errorf("deepl: %w: %s", ErrJobFailed, statusResult.Message)
 				}
-				return nil, errors.New("deepl translation failed")
+				return nil, fmt.Errorf("deepl: %w", ErrJobFailed)
 			default:
 				return nil, fmt.Errorf("unknown deepl document status: %s", statusResult.Status)
 			}
@@ -213,32 +248,46 @@ func (c *DeepLClient) TranslateDocument(ctx context.Context, reader io.Reader, o
 
 // TranslateText calls the text translation endpoint.
 func (c *DeepLClient) TranslateText(ctx context.Context, text string, sourceLang, targetLang, formality string) (string, error) {
+	translations, err := c.TranslateTexts(ctx, []string{text}, sourceLang, targetLang, formality, "")
+	if err != nil {
+		return "", err
+	}
+	return translations[0], nil
+}
+
+// TranslateTexts translates several texts in one request, keeping their order.
+func (c *DeepLClient) TranslateTexts(ctx context.Context, texts []string, sourceLang, targetLang, formality, glossaryID string) ([]string, error) {
 	translateURL := c.makeURL("translate")
 	form := url.Values{}
-	form.Set("text", text)
+	for _, text := range texts {
+		form.Add("text", text)
+	}
 	if sourceLang != "" {
 		form.Set("source_lang", strings.ToUpper(sourceLang))
 	}
-	form.Set("target_lang", strings.ToUpper(targetLang))
+	form.Set("target_lang", strings.ToUpper(TargetVariant(targetLang)))
 	if formality != "" {
 		form.Set("formality", formality)
 	}
+	if glossaryID != "" {
+		form.Set("glossary_id", glossaryID)
+	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, translateURL, strings.NewReader(form.Encode()))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	req.Header.Set("Authorization", fmt.Sprintf("DeepL-Auth-Key %s", c.apiKey))
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		return "", parseDeepLError(resp.Body)
+		return nil, parseDeepLError(resp.StatusCode, resp.Header, resp.Body)
 	}
 
 	var result struct {
@@ -247,12 +296,16 @@ func (c *DeepLClient) TranslateText(ctx context.Context, text string, sourceLang
 		} `json:"translations"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", err
+		return nil, err
+	}
+	if len(result.Translations) != len(texts) {
+		return nil, fmt.Errorf("deepl: expected %d translations, got %d", len(texts), len(result.Translations))
 	}
-	if len(result.Translations) == 0 {
-		return "", errors.New("deepl: empty translation result")
+	translations := make([]string, len(texts))
+	for i, t := range result.Translations {
+		translations[i] = t.Text
 	}
-	return result.Translations[0].Text, nil
+	return translations, nil
 }
 
 func (c *DeepLClient) makeURL(pathSuffix string) string {
@@ -261,13 +314,194 @@ func (c *DeepLClient) makeURL(pathSuffix string) string {
 	return base.String()
 }
 
-func parseDeepLError(body io.Reader) error {
-	var apiErr DeepLError
-	if err := json.NewDecoder(body).Decode(&apiErr); err != nil {
-		return err
+func (c *DeepLClient) do(req *http.Request) (*http.Response, error) {
+	return sendWithRetry(c.httpClient, c.retry, req, isIdempotentMethod(req.Method))
+}
+
+func parseDeepLError(status int, header http.Header, body io.Reader) error {
+	apiErr := DeepLError{StatusCode: status, RetryAfter: parseRetryAfter(header)}
+	if err := json.NewDecoder(body).Decode(&apiErr); err != nil || apiErr.Message == "" {
+		apiErr.Message = http.StatusText(status)
 	}
 	if apiErr.Message == "" {
-		return errors.New("deepl: unknown error")
+		apiErr.Message = "unknown error"
 	}
 	return &apiErr
 }
+
+// Usage reports character consumption for the current billing period.
+type Usage struct {
+	CharacterCount int64 `json:"character_count"`
+	CharacterLimit int64 `json:"character_limit"`
+}
+
+// Remaining returns characters left before the quota is exhausted.
+func (u Usage) Remaining() int64 {
+	if u.CharacterLimit <= u.CharacterCount {
+		return 0
+	}
+	return u.CharacterLimit - u.CharacterCount
+}
+
+// Usage queries the usage endpoint for the configured API key.
+func (c *DeepLClient) Usage(ctx context.Context) (*Usage, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.makeURL("usage"), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("DeepL-Auth-Key %s", c.apiKey))
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return nil, parseDeepLError(resp.StatusCode, resp.Header, resp.Body)
+	}
+
+	var usage Usage
+	if err := json.NewDecoder(resp.Body).Decode(&usage); err != nil {
+		return nil, err
+	}
+	return &usage, nil
+}
+
+// Languages lists DeepL's source or target languages. kind is "source" or
+// "target".
+func (c *DeepLClient) Languages(ctx context.Context, kind string) ([]Language, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.makeURL("languages")+"?type="+url.QueryEscape(kind), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("DeepL-Auth-Key %s", c.apiKey))
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return nil, parseDeepLError(resp.StatusCode, resp.Header, resp.Body)
+	}
+
+	var result []struct {
+		Language          string `json:"language"`
+		Name              string `json:"name"`
+		SupportsFormality bool   `json:"supports_formality"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	languages := make([]Language, 0, len(result))
+	for _, l := range result {
+		languages = append(languages, Language{Code: strings.ToUpper(l.Language), Name: l.Name, SupportsFormality: l.SupportsFormality})
+	}
+	return languages, nil
+}
+
+// KeyID returns a masked identifier for the API key, safe for logs.
+func (c *DeepLClient) KeyID() string {
+	if len(c.apiKey) <= 4 {
+		return "deepl:****"
+	}
+	return "deepl:…" + c.apiKey[len(c.apiKey)-4:]
+}
+
+// Health verifies the API key by querying the usage endpoint.
+func (c *DeepLClient) Health(ctx context.Context) error {
+	_, err := c.Usage(ctx)
+	return err
+}
+
+// DeepLProvider adapts DeepLClient to the Provider interface.
+type DeepLProvider struct {
+	client *DeepLClient
+}
+
+// NewDeepLProvider wraps a DeepL client.
+func NewDeepLProvider(client *DeepLClient) *DeepLProvider {
+	return &DeepLProvider{client: client}
+}
+
+// Type implements Provider.
+func (p *DeepLProvider) Type() ProviderType {
+	return ProviderDeepL
+}
+
+// Capabilities implements Provider.
+func (p *DeepLProvider) Capabilities() Capabilities {
+	return Capabilities{
+		Documents: true,
+		Text:      true,
+		Formality: true,
+		Glossary:  true,
+		XMLTags:   true,
+	}
+}
+
+// TranslateDocument implements Provider. A document uploaded by an earlier
+// attempt is resumed unless DeepL no longer knows it.
+func (p *DeepLProvider) TranslateDocument(ctx context.Context, reader io.Reader, req DocumentRequest) ([]byte, error) {
+	if job := req.resumable(ProviderDeepL); job != nil {
+		result, err := p.client.WaitDocument(ctx, DeepLDocument{DocumentID: job.ID, DocumentKey: job.Key})
+		if !errors.Is(err, ErrNotFound) {
+			return result, err
+		}
+	}
+	doc, err := p.client.SubmitDocument(ctx, reader, DocumentOptions{
+		SourceLang:  req.SourceLang,
+		TargetLang:  req.TargetLang,
+		Formality:   req.Formality,
+		GlossaryID:  req.GlossaryID,
+		TagHandling: req.TagHandling,
+		FileName:    req.FileName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	req.submitted(RemoteJob{Provider: ProviderDeepL, Engine: req.Engine, ID: doc.DocumentID, Key: doc.DocumentKey})
+	return p.client.WaitDocument(ctx, *doc)
+}
+
+// TranslateText implements Provider.
+func (p *DeepLProvider) TranslateText(ctx context.Context, text string, req TextRequest) (string, error) {
+	return p.client.TranslateText(ctx, text, req.SourceLang, req.TargetLang, req.Formality)
+}
+
+// TranslateSegments implements SegmentTranslator.
+func (p *DeepLProvider) TranslateSegments(ctx context.Context, segments []string, req TextRequest) ([]string, error) {
+	return TranslateSegments(ctx, segments, deepLTextChunkChars, 0, func(ctx context.Context, batch SegmentBatch) ([]string, error) {
+		targets := make([]string, 0, len(batch.Segments))
+		for start := 0; start < len(batch.Segments); start += deepLMaxTexts {
+			end := start + deepLMaxTexts
+			if end > len(batch.Segments) {
+				end = len(batch.Segments)
+			}
+			translated, err := p.client.TranslateTexts(ctx, batch.Segments[start:end], req.SourceLang, req.TargetLang, req.Formality, req.GlossaryID)
+			if err != nil {
+				return nil, err
+			}
+			targets = append(targets, translated...)
+		}
+		return targets, nil
+	})
+}
+
+// Languages implements LanguageSource.
+func (p *DeepLProvider) Languages(ctx context.Context) (*LanguageSet, error) {
+	source, err := p.client.Languages(ctx, "source")
+	if err != nil {
+		return nil, err
+	}
+	target, err := p.client.Languages(ctx, "target")
+	if err != nil {
+		return nil, err
+	}
+	return &LanguageSet{Source: source, Target: target}, nil
+}
+
+// Health implements Provider.
+func (p *DeepLProvider) Health(ctx context.Context) error {
+	return p.client.Health(ctx)
+}
@@ -0,0 +1,137 @@
+package translation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// GlossaryEntry is a single source/target term pair.
+type GlossaryEntry struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// GlossaryInfo describes a glossary hosted by DeepL.
+type GlossaryInfo struct {
+	GlossaryID   string    `json:"glossary_id"`
+	Name         string    `json:"name"`
+	Ready        bool      `json:"ready"`
+	SourceLang   string    `json:"source_lang"`
+	TargetLang   string    `json:"target_lang"`
+	CreationTime time.Time `json:"creation_time"`
+	EntryCount   int       `json:"entry_count"`
+}
+
+// CreateGlossary uploads entries as a new DeepL glossary.
+func (c *DeepLClient) CreateGlossary(ctx context.Context, name, sourceLang, targetLang string, entries []GlossaryEntry) (*GlossaryInfo, error) {
+	if len(entries) == 0 {
+		return nil, errors.New("deepl: glossary has no entries")
+	}
+	form := url.Values{}
+	form.Set("name", name)
+	form.Set("source_lang", strings.ToLower(sourceLang))
+	form.Set("target_lang", strings.ToLower(targetLang))
+	form.Set("entries", EncodeGlossaryTSV(entries))
+	form.Set("entries_format", "tsv")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.makeURL("glossaries"), strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("DeepL-Auth-Key %s", c.apiKey))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return nil, parseDeepLError(resp.StatusCode, resp.Header, resp.Body)
+	}
+
+	var info GlossaryInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// GetGlossary returns glossary metadata.
+func (c *DeepLClient) GetGlossary(ctx context.Context, glossaryID string) (*GlossaryInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.makeURL(path.Join("glossaries", glossaryID)), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("DeepL-Auth-Key %s", c.apiKey))
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return nil, parseDeepLError(resp.StatusCode, resp.Header, resp.Body)
+	}
+
+	var info GlossaryInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// GlossaryEntries downloads the entries of a glossary.
+func (c *DeepLClient) GlossaryEntries(ctx context.Context, glossaryID string) ([]GlossaryEntry, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.makeURL(path.Join("glossaries", glossaryID, "entries")), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("DeepL-Auth-Key %s", c.apiKey))
+	req.Header.Set("Accept", "text/tab-separated-values")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return nil, parseDeepLError(resp.StatusCode, resp.Header, resp.Body)
+	}
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGlossaryEntries(string(raw), "tsv")
+}
+
+// DeleteGlossary removes a glossary from DeepL.
+func (c *DeepLClient) DeleteGlossary(ctx context.Context, glossaryID string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.makeURL(path.Join("glossaries", glossaryID)), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("DeepL-Auth-Key %s", c.apiKey))
+
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if resp.StatusCode >= 400 {
+		return parseDeepLError(resp.StatusCode, resp.Header, resp.Body)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package translation
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Detection is the result of offline source-language detection.
+type Detection struct {
+	Language   string  `json:"language"`
+	Confidence float64 `json:"confidence"`
+}
+
+// detectSampleRunes bounds how much text is inspected.
+const detectSampleRunes = 20000
+
+// detectMinHits is the minimum number of profile words for a verdict.
+const detectMinHits = 3
+
+// languageProfiles hold the most frequent function words per language.
+var languageProfiles = map[string][]string{
+	"EN": {"the", "and", "of", "to", "is", "in", "that", "it", "with", "for", "this", "are", "was", "be", "have", "not", "you", "on", "by", "which"},
+	"DE": {"der", "die", "und", "das", "ist", "nicht", "ein", "eine", "zu", "den", "mit", "sich", "des", "auf", "für", "dem", "wird", "sie", "auch", "von"},
+	"FR": {"le", "la", "les", "et", "des", "est", "une", "un", "du", "que", "pour", "dans", "qui", "pas", "sur", "au", "avec", "ce", "sont", "nous"},
+	"ES": {"el", "los", "las", "y", "es", "en", "que", "una", "por", "para", "con", "del", "se", "no", "su", "al", "como", "más", "pero", "está"},
+	"IT": {"il", "di", "che", "è", "e", "la", "per", "un", "non", "una", "sono", "del", "della", "con", "gli", "le", "si", "da", "come", "anche"},
+	"PT": {"o", "os", "as", "e", "de", "que", "não", "uma", "um", "do", "da", "para", "com", "em", "é", "se", "mais", "por", "são", "dos"},
+	"NL": {"de", "het", "een", "en", "van", "is", "dat", "niet", "op", "te", "zijn", "met", "voor", "er", "ook", "aan", "wordt", "maar", "bij", "ik"},
+	"PL": {"i", "w", "nie", "na", "się", "z", "jest", "do", "to", "że", "o", "jak", "ale", "po", "co", "tak", "przez", "od", "jego", "dla"},
+	"CS": {"a", "je", "se", "na", "v", "že", "to", "s", "z", "do", "jsou", "jako", "ale", "tak", "pro", "by", "který", "nebo", "také", "jeho"},
+	"SK": {"a", "je", "sa", "na", "v", "že", "to", "s", "z", "do", "sú", "ako", "ale", "tak", "pre", "by", "ktorý", "alebo", "aj", "jeho"},
+	"SV": {"och", "att", "det", "som", "en", "är", "av", "för", "med", "till", "den", "inte", "på", "har", "om", "jag", "ett", "de", "men", "var"},
+	"DA": {"og", "at", "det", "er", "en", "af", "til", "den", "som", "på", "med", "for", "ikke", "har", "de", "jeg", "et", "der", "men", "var"},
+	"NB": {"og", "i", "det", "er", "som", "en", "på", "til", "av", "for", "med", "ikke", "har", "de", "jeg", "et", "den", "men", "var", "om"},
+	"FI": {"ja", "on", "ei", "se", "että", "oli", "hän", "mutta", "ole", "kun", "niin", "myös", "tai", "joka", "ovat", "kuin", "sen", "tämä", "mitä", "jos"},
+	"HU": {"a", "az", "és", "hogy", "nem", "is", "egy", "van", "meg", "de", "ez", "csak", "már", "el", "még", "mint", "volt", "ki", "vagy", "kell"},
+	"RO": {"și", "de", "în", "la", "cu", "nu", "o", "că", "pe", "un", "este", "din", "se", "mai", "sunt", "care", "pentru", "ca", "fi", "ce"},
+	"TR": {"ve", "bir", "bu", "da", "de", "için", "ile", "ne", "çok", "daha", "gibi", "olarak", "ama", "kadar", "olan", "var", "değil", "ben", "sonra", "mi"},
+	"ID": {"dan", "yang", "di", "ini", "itu", "dengan", "untuk", "tidak", "dari", "dalam", "akan", "pada", "juga", "ke", "ada", "saya", "karena", "oleh", "bisa", "mereka"},
+	"ET": {"ja", "on", "ei", "et", "see", "oli", "ka", "kui", "mis", "aga", "ta", "oma", "või", "nii", "siis", "seda", "mida", "kes", "veel", "välja"},
+	"LV": {"un", "ir", "ka", "no", "uz", "par", "ar", "kas", "bet", "arī", "tas", "lai", "to", "vai", "nav", "viņš", "jau", "pie", "kā", "būt"},
+	"LT": {"ir", "kad", "tai", "su", "į", "o", "kaip", "bet", "yra", "iš", "ne", "jis", "buvo", "to", "tik", "ar", "už", "dar", "apie", "nuo"},
+	"SL": {"in", "je", "da", "se", "na", "za", "so", "ki", "ne", "v", "z", "pa", "tudi", "bi", "kot", "pri", "od", "ali", "ta", "sem"},
+	"RU": {"и", "в", "не", "на", "что", "он", "с", "как", "это", "по", "но", "из", "к", "у", "за", "от", "так", "же", "вы", "был"},
+	"UK": {"і", "в", "не", "на", "що", "він", "з", "як", "це", "по", "але", "до", "у", "за", "від", "так", "же", "ви", "був", "та"},
+	"BG": {"и", "в", "не", "на", "че", "той", "с", "като", "това", "по", "но", "за", "от", "се", "да", "са", "е", "към", "си", "ще"},
+}
+
+// cyrillicMarkers are letters unique to one Cyrillic alphabet.
+var cyrillicMarkers = map[rune]string{
+	'ы': "RU", 'э': "RU", 'ё': "RU",
+	'і': "UK", 'ї': "UK", 'є': "UK", 'ґ': "UK",
+}
+
+var profileIndex = buildProfileIndex()
+
+func buildProfileIndex() map[string][]string {
+	index := make(map[string][]string)
+	for lang, words := range languageProfiles {
+		for _, word := range words {
+			index[word] = append(index[word], lang)
+		}
+	}
+	return index
+}
+
+// DetectLanguage guesses the language of text offline. Scripts used by a
+// single language decide directly; Latin and Cyrillic text is scored against
+// function-word profiles. An empty Language means the text is inconclusive.
+func DetectLanguage(text string) Detection {
+	scripts := make(map[string]int)
+	letters := 0
+	sample := 0
+	markers := make(map[string]int)
+	for _, r := range text {
+		if sample++; sample > detectSampleRunes {
+			break
+		}
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		switch {
+		case unicode.Is(unicode.Hangul, r):
+			scripts["KO"]++
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			scripts["JA"]++
+		case unicode.Is(unicode.Han, r):
+			scripts["ZH"]++
+		case unicode.Is(unicode.Greek, r):
+			scripts["EL"]++
+		case unicode.Is(unicode.Arabic, r):
+			scripts["AR"]++
+		case unicode.Is(unicode.Cyrillic, r):
+			scripts["cyrillic"]++
+			if lang, ok := cyrillicMarkers[unicode.ToLower(r)]; ok {
+				markers[lang]++
+			}
+		case unicode.Is(unicode.Latin, r):
+			scripts["latin"]++
+		}
+	}
+	if letters == 0 {
+		return Detection{}
+	}
+
+	// Any kana means Japanese, even when Han characters dominate.
+	if scripts["JA"] > 0 && scripts["JA"]+scripts["ZH"] > letters/2 {
+		return Detection{Language: "JA", Confidence: roundConfidence(float64(scripts["JA"]+scripts["ZH"]) / float64(letters))}
+	}
+	dominant, count := "", 0
+	for script, n := range scripts {
+		if n > count {
+			dominant, count = script, n
+		}
+	}
+	share := float64(count) / float64(letters)
+	if dominant != "latin" && dominant != "cyrillic" {
+		return Detection{Language: dominant, Confidence: roundConfidence(share)}
+	}
+
+	scores := make(map[string]float64)
+	for _, word := range strings.FieldsFunc(strings.ToLower(truncateRunes(text, detectSampleRunes)), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}) {
+		for _, lang := range profileIndex[word] {
+			scores[lang]++
+		}
+	}
+	for lang, n := range markers {
+		scores[lang] += float64(n) / 2
+	}
+
+	best, bestScore, runnerUp := "", 0.0, 0.0
+	for lang, score := range scores {
+		switch {
+		case score > bestScore || (score == bestScore && lang < best):
+			runnerUp = bestScore
+			best, bestScore = lang, score
+		case score > runnerUp:
+			runnerUp = score
+		}
+	}
+	if bestScore < detectMinHits {
+		return Detection{}
+	}
+	// Related languages share function words, so confidence grows with the
+	// lead over the runner-up; short samples are discounted.
+	margin := 0.5 + 0.5*(bestScore-runnerUp)/bestScore
+	coverage := math.Min(1, bestScore/10)
+	return Detection{Language: best, Confidence: roundConfidence(share * margin * coverage)}
+}
+
+func truncateRunes(text string, n int) string {
+	count := 0
+	for i := range text {
+		if count == n {
+			return text[:i]
+		}
+		count++
+	}
+	return text
+}
+
+func roundConfidence(v float64) float64 {
+	return math.Round(v*100) / 100
+}
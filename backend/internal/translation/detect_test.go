@@ -0,0 +1,33 @@
+package translation
+
+import "testing"
+
+func TestDetectLanguage(t *testing.T) {
+	cases := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{"english", "The quick brown fox jumps over the lazy dog. It is one of the most famous sentences in the English language, and it is used to test fonts because it contains all of the letters.", "EN"},
+		{"german", "Die Übersetzung ist nicht fertig, weil der Text noch nicht geprüft wurde. Das Team wird sich morgen mit dem Kunden treffen und die offenen Fragen auch klären.", "DE"},
+		{"french", "Le document est prêt pour la traduction, mais nous devons vérifier les termes avec le client. Il est important que la terminologie soit cohérente dans tous les chapitres.", "FR"},
+		{"spanish", "El documento está listo para la traducción, pero tenemos que revisar los términos con el cliente. Es importante que la terminología sea coherente en todos los capítulos.", "ES"},
+		{"ukrainian", "Це речення написане українською мовою, і воно містить літери, які є тільки в українській абетці. Ми перевіряємо, що детектор працює правильно.", "UK"},
+		{"russian", "Это предложение написано на русском языке, и оно содержит буквы, которые есть только в русском алфавите. Мы проверяем, что детектор работает.", "RU"},
+		{"japanese", "これは日本語の文章です。翻訳の品質を確認しています。", "JA"},
+		{"korean", "이것은 한국어 문장입니다. 번역 품질을 확인하고 있습니다.", "KO"},
+		{"too short", "Hello", ""},
+		{"numbers", "12345 67890", ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := DetectLanguage(tc.text)
+			if got.Language != tc.expected {
+				t.Fatalf("expected %q got %+v", tc.expected, got)
+			}
+			if tc.expected != "" && (got.Confidence <= 0 || got.Confidence > 1) {
+				t.Fatalf("confidence out of range: %+v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package translation
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// FailoverReason classifies provider errors that may trigger failover.
+type FailoverReason string
+
+const (
+	FailoverServerError   FailoverReason = "server_error"
+	FailoverQuotaExceeded FailoverReason = "quota_exceeded"
+	FailoverRateLimited   FailoverReason = "rate_limited"
+	FailoverTimeout       FailoverReason = "timeout"
+	FailoverUnreachable   FailoverReason = "unreachable"
+)
+
+// deepLQuotaExceeded is DeepL's non-standard "Quota Exceeded" status.
+const deepLQuotaExceeded = 456
+
+// Route is a provider/engine pair able to serve a model.
+type Route struct {
+	Provider ProviderType `json:"provider"`
+	Engine   string       `json:"engine"`
+}
+
+// Routes returns the model's primary route followed by its fallbacks.
+func (m Model) Routes() []Route {
+	routes := make([]Route, 0, len(m.Fallbacks)+1)
+	routes = append(routes, Route{Provider: m.Provider, Engine: m.Engine})
+	return append(routes, m.Fallbacks...)
+}
+
+// FailoverPolicy decides which errors move a job to the next route.
+type FailoverPolicy struct {
+	Enabled bool
+	Reasons map[FailoverReason]bool
+}
+
+// ParseFailoverPolicy builds a policy from reason names.
+func ParseFailoverPolicy(enabled bool, reasons []string) (FailoverPolicy, error) {
+	policy := FailoverPolicy{Enabled: enabled, Reasons: make(map[FailoverReason]bool)}
+	for _, raw := range reasons {
+		reason := FailoverReason(strings.TrimSpace(strings.ToLower(raw)))
+		switch reason {
+		case "":
+			continue
+		case FailoverServerError, FailoverQuotaExceeded, FailoverRateLimited, FailoverTimeout, FailoverUnreachable:
+			policy.Reasons[reason] = true
+		default:
+			return FailoverPolicy{}, fmt.Errorf("unknown failover reason %s", raw)
+		}
+	}
+	return policy, nil
+}
+
+// ShouldFailover reports whether err warrants trying the next route.
+func (p FailoverPolicy) ShouldFailover(err error) bool {
+	if !p.Enabled || err == nil {
+		return false
+	}
+	reason, ok := ClassifyError(err)
+	return ok && p.Reasons[reason]
+}
+
+// ClassifyError maps provider errors to failover reasons.
+func ClassifyError(err error) (FailoverReason, bool) {
+	var statusErr interface{ HTTPStatus() int }
+	if errors.As(err, &statusErr) {
+		switch status := statusErr.HTTPStatus(); {
+		case status == deepLQuotaExceeded:
+			return FailoverQuotaExceeded, true
+		case status == http.StatusTooManyRequests || status == deepLTooManyRequests:
+			return FailoverRateLimited, true
+		case status == http.StatusRequestTimeout || status == http.StatusGatewayTimeout:
+			return FailoverTimeout, true
+		case status >= 500:
+			return FailoverServerError, true
+		}
+		return "", false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return FailoverTimeout, true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return FailoverTimeout, true
+		}
+		return FailoverUnreachable, true
+	}
+	return "", false
+}
+
+// FailoverFunc is notified when a route fails and the next one is tried.
+type FailoverFunc func(failed Route, err error, next Route)
+
+// TranslateDocument translates via the model's routes in order, moving on
+// when the policy allows it and the route has not accepted a remote job. It
+// returns the route that produced the output.
+func (r *Registry) TranslateDocument(ctx context.Context, model Model, data []byte, req DocumentRequest, policy FailoverPolicy, onFailover FailoverFunc) ([]byte, Route, error) {
+	var result []byte
+	route, err := r.failover(ctx, model, req, policy, onFailover, func(provider Provider, attempt DocumentRequest) error {
+		var err error
+		if attempt.usesSegments() {
+			result, err = translateWithMemory(ctx, provider, data, attempt)
+		} else if result, err = provider.TranslateDocument(ctx, bytes.NewReader(data), attempt); err == nil {
+			err = CheckDocumentPlaceholders(attempt.FileName, attempt.TargetLang, data, result)
+		}
+		return err
+	})
+	return result, route, err
+}
+
+// TranslateSegments translates the segments of a req.FileName document via
+// the model's routes like TranslateDocument, for documents translated in
+// parts. Placeholders are masked and stored segments come from req.Memory.
+func (r *Registry) TranslateSegments(ctx context.Context, model Model, segments []string, req DocumentRequest, policy FailoverPolicy, onFailover FailoverFunc) ([]string, Route, error) {
+	var targets []string
+	route, err := r.failover(ctx, model, req, policy, onFailover, func(provider Provider, attempt DocumentRequest) error {
+		var err error
+		targets, err = translateSegmentsWithMemory(ctx, provider, segments, attempt)
+		return err
+	})
+	return targets, route, err
+}
+
+// failover runs translate on the model's routes in order until one
+// succeeds or the policy stops it, and returns the last route tried.
+func (r *Registry) failover(ctx context.Context, model Model, req DocumentRequest, policy FailoverPolicy, onFailover FailoverFunc, translate func(provider Provider, attempt DocumentRequest) error) (Route, error) {
+	routes := make([]Route, 0, len(model.Fallbacks)+1)
+	for _, route := range model.Routes() {
+		if _, err := r.Get(route.Provider); err == nil {
+			routes = append(routes, route)
+		}
+	}
+	if len(routes) == 0 {
+		return Route{}, fmt.Errorf("provider %s not registered", model.Provider)
+	}
+	if req.Job != nil {
+		// Continue on the route that already holds a remote job rather than
+		// submitting the document to an earlier route again.
+		for i, route := range routes {
+			if route.Provider == req.Job.Provider && route.Engine == req.Job.Engine {
+				routes = routes[i:]
+				break
+			}
+		}
+	}
+
+	var lastErr error
+	for i, route := range routes {
+		provider, err := r.Get(route.Provider)
+		if err != nil {
+			return route, err
+		}
+		attempt := req
+		attempt.Engine = route.Engine
+		if route.Provider != model.Provider {
+			// Glossary IDs are provider specific.
+			attempt.GlossaryID = ""
+		}
+		// Once a route holds a remote job the document is billed there;
+		// the error is returned so a retry resumes that job rather than
+		// paying another route for the same document.
+		submitted := req.Job != nil && req.Job.Provider == route.Provider && req.Job.Engine == route.Engine
+		attempt.OnSubmit = func(job RemoteJob) {
+			submitted = true
+			if req.OnSubmit != nil {
+				req.OnSubmit(job)
+			}
+		}
+		err = translate(provider, attempt)
+		if err == nil {
+			return route, nil
+		}
+		lastErr = err
+		if i == len(routes)-1 || ctx.Err() != nil || submitted || !policy.ShouldFailover(err) {
+			return route, err
+		}
+		if onFailover != nil {
+			onFailover(route, err, routes[i+1])
+		}
+	}
+	return Route{}, lastErr
+}
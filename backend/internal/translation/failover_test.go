@@ -0,0 +1,136 @@
+package translation
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+type stubProvider struct {
+	providerType ProviderType
+	err          error
+	calls        int
+	// submit makes TranslateDocument accept a remote job before failing.
+	submit bool
+}
+
+func (p *stubProvider) Type() ProviderType           { return p.providerType }
+func (p *stubProvider) Capabilities() Capabilities   { return Capabilities{Documents: true} }
+func (p *stubProvider) Health(context.Context) error { return nil }
+func (p *stubProvider) TranslateText(context.Context, string, TextRequest) (string, error) {
+	return "", p.err
+}
+func (p *stubProvider) TranslateDocument(_ context.Context, reader io.Reader, req DocumentRequest) ([]byte, error) {
+	p.calls++
+	if p.submit && req.OnSubmit != nil {
+		req.OnSubmit(RemoteJob{Provider: p.providerType, Engine: req.Engine, ID: "job"})
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	return []byte(req.Engine), nil
+}
+
+func TestRegistryTranslateDocumentFailover(t *testing.T) {
+	model := Model{
+		Provider:  ProviderDeepL,
+		Engine:    "deepl-pro",
+		Fallbacks: []Route{{Provider: ProviderOTranslator, Engine: "otranslator-elite"}},
+	}
+	policy, err := ParseFailoverPolicy(true, []string{"quota_exceeded"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	primary := &stubProvider{providerType: ProviderDeepL, err: &DeepLError{StatusCode: 456, Message: "Quota exceeded"}}
+	fallback := &stubProvider{providerType: ProviderOTranslator}
+	registry := NewRegistry(primary, fallback)
+
+	out, route, err := registry.TranslateDocument(context.Background(), model, []byte("x"), DocumentRequest{}, policy, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if route.Provider != ProviderOTranslator || string(out) != "otranslator-elite" {
+		t.Fatalf("expected fallback route, got %+v (%s)", route, out)
+	}
+
+	primary.err = &DeepLError{StatusCode: 400, Message: "Bad request"}
+	fallback.calls = 0
+	if _, _, err := registry.TranslateDocument(context.Background(), model, []byte("x"), DocumentRequest{}, policy, nil); err == nil {
+		t.Fatal("expected permanent error to be returned")
+	}
+	if fallback.calls != 0 {
+		t.Fatalf("fallback should not run for permanent errors")
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	cases := []struct {
+		err    error
+		reason FailoverReason
+		ok     bool
+	}{
+		{&DeepLError{StatusCode: 503}, FailoverServerError, true},
+		{&OTranslatorError{StatusCode: 429}, FailoverRateLimited, true},
+		{context.DeadlineExceeded, FailoverTimeout, true},
+		{&DeepLError{StatusCode: 403}, "", false},
+		{errors.New("boom"), "", false},
+	}
+	for _, tc := range cases {
+		reason, ok := ClassifyError(tc.err)
+		if reason != tc.reason || ok != tc.ok {
+			t.Fatalf("%v: expected %s/%v got %s/%v", tc.err, tc.reason, tc.ok, reason, ok)
+		}
+	}
+}
+
+func TestRegistryTranslateDocumentResumesJobRoute(t *testing.T) {
+	model := Model{
+		Provider:  ProviderDeepL,
+		Engine:    "deepl-pro",
+		Fallbacks: []Route{{Provider: ProviderOTranslator, Engine: "otranslator-elite"}},
+	}
+	primary := &stubProvider{providerType: ProviderDeepL}
+	fallback := &stubProvider{providerType: ProviderOTranslator}
+	registry := NewRegistry(primary, fallback)
+
+	req := DocumentRequest{Job: &RemoteJob{Provider: ProviderOTranslator, Engine: "otranslator-elite", ID: "job-1"}}
+	_, route, err := registry.TranslateDocument(context.Background(), model, []byte("x"), req, FailoverPolicy{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if route.Provider != ProviderOTranslator || primary.calls != 0 {
+		t.Fatalf("expected to resume on the fallback route, got %+v after %d primary calls", route, primary.calls)
+	}
+}
+
+func TestRegistryTranslateDocumentKeepsSubmittedJobRoute(t *testing.T) {
+	model := Model{
+		Provider:  ProviderDeepL,
+		Engine:    "deepl-pro",
+		Fallbacks: []Route{{Provider: ProviderOTranslator, Engine: "otranslator-elite"}},
+	}
+	policy, err := ParseFailoverPolicy(true, []string{"server_error", "timeout"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	primary := &stubProvider{providerType: ProviderDeepL, err: &DeepLError{StatusCode: 503, Message: "Unavailable"}, submit: true}
+	fallback := &stubProvider{providerType: ProviderOTranslator}
+	registry := NewRegistry(primary, fallback)
+
+	var jobs []RemoteJob
+	req := DocumentRequest{OnSubmit: func(job RemoteJob) { jobs = append(jobs, job) }}
+	if _, _, err := registry.TranslateDocument(context.Background(), model, []byte("x"), req, policy, nil); err == nil {
+		t.Fatal("expected the polling error to be returned")
+	}
+	if fallback.calls != 0 || len(jobs) != 1 || jobs[0].Provider != ProviderDeepL {
+		t.Fatalf("expected no failover after submission, got %d fallback calls and jobs %+v", fallback.calls, jobs)
+	}
+
+	primary.submit = false
+	req.Job = &RemoteJob{Provider: ProviderDeepL, Engine: "deepl-pro", ID: "job"}
+	if _, _, err := registry.TranslateDocument(context.Background(), model, []byte("x"), req, policy, nil); err == nil || fallback.calls != 0 {
+		t.Fatalf("expected a resumed job not to fail over, got %v after %d fallback calls", err, fallback.calls)
+	}
+}
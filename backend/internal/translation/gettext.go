@@ -0,0 +1,207 @@
+package translation
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var gettextKeywordPattern = regexp.MustCompile(`^(msgctxt|msgid_plural|msgid|msgstr(?:\[\d+\])?)\s+(".*")\s*$`)
+
+// gettextField is a keyword of a PO entry with its value, spanning the lines
+// [start, end).
+type gettextField struct {
+	keyword string
+	value   string
+	start   int
+	end     int
+}
+
+type gettextEntry []gettextField
+
+func (e gettextEntry) field(keyword string) *gettextField {
+	for i := range e {
+		if e[i].keyword == keyword {
+			return &e[i]
+		}
+	}
+	return nil
+}
+
+// msgstrs returns the msgstr fields in plural index order.
+func (e gettextEntry) msgstrs() []*gettextField {
+	var fields []*gettextField
+	for i := range e {
+		if strings.HasPrefix(e[i].keyword, "msgstr") {
+			fields = append(fields, &e[i])
+		}
+	}
+	return fields
+}
+
+// rewriteGettext translates the messages of a PO/POT catalog into their
+// msgstr, keeping comments, flags, contexts and the header. A plural entry
+// has two segments: msgstr[0] receives the singular, every other plural
+// form the plural.
+func rewriteGettext(data []byte, replace func(string) string) ([]byte, error) {
+	lines := strings.Split(string(data), "\n")
+	entries, err := parseGettext(lines)
+	if err != nil {
+		return nil, err
+	}
+	rewritten := make(map[int][]string)
+	skip := make(map[int]int)
+	for _, entry := range entries {
+		msgid := entry.field("msgid")
+		if msgid == nil || strings.TrimSpace(msgid.value) == "" {
+			continue
+		}
+		singular := replaceTrimmed(msgid.value, replace)
+		plural := singular
+		if field := entry.field("msgid_plural"); field != nil {
+			plural = replaceTrimmed(field.value, replace)
+		}
+		for _, field := range entry.msgstrs() {
+			value := singular
+			if field.keyword != "msgstr" && field.keyword != "msgstr[0]" {
+				value = plural
+			}
+			rewritten[field.start] = formatGettextField(field.keyword, value)
+			skip[field.start] = field.end
+		}
+	}
+	out := make([]string, 0, len(lines))
+	for i := 0; i < len(lines); i++ {
+		if replacement, ok := rewritten[i]; ok {
+			out = append(out, replacement...)
+			i = skip[i] - 1
+			continue
+		}
+		out = append(out, lines[i])
+	}
+	return []byte(strings.Join(out, "\n")), nil
+}
+
+// gettextTargets lists the translations of a PO catalog aligned with the
+// segments rewriteGettext reads.
+func gettextTargets(data []byte, _ string) ([]string, error) {
+	entries, err := parseGettext(strings.Split(string(data), "\n"))
+	if err != nil {
+		return nil, err
+	}
+	var targets []string
+	for _, entry := range entries {
+		msgid := entry.field("msgid")
+		if msgid == nil || strings.TrimSpace(msgid.value) == "" {
+			continue
+		}
+		msgstrs := entry.msgstrs()
+		target := func(i int) string {
+			if len(msgstrs) == 0 {
+				return ""
+			}
+			if i >= len(msgstrs) {
+				i = len(msgstrs) - 1
+			}
+			return strings.TrimSpace(msgstrs[i].value)
+		}
+		targets = append(targets, target(0))
+		if field := entry.field("msgid_plural"); field != nil && strings.TrimSpace(field.value) != "" {
+			targets = append(targets, target(1))
+		}
+	}
+	return targets, nil
+}
+
+func parseGettext(lines []string) ([]gettextEntry, error) {
+	var entries []gettextEntry
+	var entry gettextEntry
+	var current *gettextField
+	flush := func() {
+		if len(entry) > 0 {
+			entries = append(entries, entry)
+		}
+		entry = nil
+		current = nil
+	}
+	for i, raw := range lines {
+		line := strings.TrimSpace(strings.TrimSuffix(raw, "\r"))
+		if strings.HasPrefix(line, `"`) && current != nil {
+			value, err := unquoteGettext(line)
+			if err != nil {
+				return nil, fmt.Errorf("invalid po line %d: %w", i+1, err)
+			}
+			current.value += value
+			current.end = i + 1
+			continue
+		}
+		match := gettextKeywordPattern.FindStringSubmatch(line)
+		if match == nil {
+			// Comments and blank lines end the field; comments belong to
+			// the next entry, so they end a complete entry too.
+			current = nil
+			if entry.field("msgstr") != nil || entry.field("msgstr[0]") != nil {
+				flush()
+			}
+			continue
+		}
+		keyword := match[1]
+		if (keyword == "msgctxt" || keyword == "msgid") && entry.field("msgid") != nil {
+			flush()
+		}
+		value, err := unquoteGettext(match[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid po line %d: %w", i+1, err)
+		}
+		entry = append(entry, gettextField{keyword: keyword, value: value, start: i, end: i + 1})
+		current = &entry[len(entry)-1]
+	}
+	flush()
+	return entries, nil
+}
+
+func unquoteGettext(quoted string) (string, error) {
+	if len(quoted) < 2 || quoted[0] != '"' || quoted[len(quoted)-1] != '"' {
+		return "", errors.New("unterminated string")
+	}
+	body := quoted[1 : len(quoted)-1]
+	var builder strings.Builder
+	for i := 0; i < len(body); i++ {
+		if body[i] != '\\' || i == len(body)-1 {
+			builder.WriteByte(body[i])
+			continue
+		}
+		i++
+		switch body[i] {
+		case 'n':
+			builder.WriteByte('\n')
+		case 't':
+			builder.WriteByte('\t')
+		case 'r':
+			builder.WriteByte('\r')
+		default:
+			builder.WriteByte(body[i])
+		}
+	}
+	return builder.String(), nil
+}
+
+var gettextEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\r", `\r`)
+
+// formatGettextField writes a field on one line, or wrapped after each
+// newline the way gettext tools do for multi-line messages.
+func formatGettextField(keyword, value string) []string {
+	parts := strings.SplitAfter(value, "\n")
+	if len(parts) > 1 && parts[len(parts)-1] == "" {
+		parts = parts[:len(parts)-1]
+	}
+	if len(parts) <= 1 {
+		return []string{keyword + ` "` + gettextEscaper.Replace(value) + `"`}
+	}
+	lines := []string{keyword + ` ""`}
+	for _, part := range parts {
+		lines = append(lines, `"`+gettextEscaper.Replace(part)+`"`)
+	}
+	return lines
+}
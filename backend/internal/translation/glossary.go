@@ -0,0 +1,86 @@
+package translation
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MaxGlossaryEntries caps uploaded glossaries to keep requests small.
+const MaxGlossaryEntries = 10000
+
+// ParseGlossaryEntries parses CSV or TSV glossary content into entries.
+// Each row holds a source and target term; blank lines are ignored.
+func ParseGlossaryEntries(content, format string) ([]GlossaryEntry, error) {
+	var rows [][]string
+	switch strings.ToLower(format) {
+	case "csv":
+		reader := csv.NewReader(strings.NewReader(content))
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		records, err := reader.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("invalid csv: %w", err)
+		}
+		rows = records
+	case "tsv", "":
+		for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			rows = append(rows, strings.Split(line, "\t"))
+		}
+	default:
+		return nil, fmt.Errorf("unsupported glossary format %s", format)
+	}
+
+	entries := make([]GlossaryEntry, 0, len(rows))
+	seen := make(map[string]struct{}, len(rows))
+	for i, row := range rows {
+		if len(row) == 1 && strings.TrimSpace(row[0]) == "" {
+			continue
+		}
+		if len(row) < 2 {
+			return nil, fmt.Errorf("line %d: expected source and target term", i+1)
+		}
+		source := strings.TrimSpace(row[0])
+		target := strings.TrimSpace(row[1])
+		if source == "" || target == "" {
+			return nil, fmt.Errorf("line %d: empty term", i+1)
+		}
+		if _, dup := seen[source]; dup {
+			return nil, fmt.Errorf("line %d: duplicate source term %q", i+1, source)
+		}
+		seen[source] = struct{}{}
+		entries = append(entries, GlossaryEntry{Source: source, Target: target})
+	}
+	if len(entries) == 0 {
+		return nil, errors.New("glossary has no entries")
+	}
+	if len(entries) > MaxGlossaryEntries {
+		return nil, fmt.Errorf("glossary exceeds %d entries", MaxGlossaryEntries)
+	}
+	return entries, nil
+}
+
+// EncodeGlossaryTSV renders entries in DeepL's TSV format.
+func EncodeGlossaryTSV(entries []GlossaryEntry) string {
+	var builder strings.Builder
+	for _, e := range entries {
+		builder.WriteString(strings.ReplaceAll(e.Source, "\t", " "))
+		builder.WriteString("\t")
+		builder.WriteString(strings.ReplaceAll(e.Target, "\t", " "))
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+// BaseLanguage strips regional variants, e.g. "EN-GB" becomes "en".
+func BaseLanguage(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if i := strings.IndexAny(code, "-_"); i >= 0 {
+		code = code[:i]
+	}
+	return code
+}
@@ -0,0 +1,133 @@
+package translation
+
+import (
+	"context"
+	"strings"
+)
+
+// Language is a language code offered by a provider.
+type Language struct {
+	Code              string `json:"code"`
+	Name              string `json:"name"`
+	SupportsFormality bool   `json:"supportsFormality"`
+}
+
+// LanguageSet lists the source and target languages of a provider.
+type LanguageSet struct {
+	Source []Language `json:"source"`
+	Target []Language `json:"target"`
+}
+
+// LanguageSource is implemented by providers that can report their
+// supported languages.
+type LanguageSource interface {
+	Languages(ctx context.Context) (*LanguageSet, error)
+}
+
+// SupportsSource reports whether code is a valid source language.
+func (s LanguageSet) SupportsSource(code string) bool {
+	_, ok := findLanguage(s.Source, code)
+	return ok
+}
+
+// TargetLanguage returns the target language matching code.
+func (s LanguageSet) TargetLanguage(code string) (Language, bool) {
+	return findLanguage(s.Target, code)
+}
+
+// defaultVariants are the regional variants used for a bare base code when a
+// provider only lists variants, as DeepL does for English and Portuguese.
+var defaultVariants = map[string]string{
+	"EN": "EN-GB",
+	"PT": "PT-PT",
+}
+
+// TargetVariant returns the default regional variant of a bare base code such
+// as "EN", or code unchanged.
+func TargetVariant(code string) string {
+	if variant, ok := defaultVariants[strings.ToUpper(strings.TrimSpace(code))]; ok {
+		return variant
+	}
+	return code
+}
+
+// findLanguage matches code exactly or, for regional variants such as
+// "de-AT", by its base language when only the base is listed. A bare base
+// code matches its default variant when only variants are listed, e.g. "EN"
+// against EN-GB/EN-US.
+func findLanguage(languages []Language, code string) (Language, bool) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return Language{}, false
+	}
+	base := BaseLanguage(code)
+	var baseMatch *Language
+	for i, lang := range languages {
+		if strings.EqualFold(lang.Code, code) {
+			return lang, true
+		}
+		if baseMatch == nil && strings.EqualFold(lang.Code, base) {
+			baseMatch = &languages[i]
+		}
+	}
+	if baseMatch != nil {
+		return *baseMatch, true
+	}
+	if variant := TargetVariant(code); variant != code {
+		return findLanguage(languages, variant)
+	}
+	return Language{}, false
+}
+
+// StaticLanguages is the language list of providers without a languages
+// endpoint (OTranslator, LLM and mock).
+var StaticLanguages = LanguageSet{
+	Source: staticLanguages(false),
+	Target: staticLanguages(true),
+}
+
+var staticLanguageList = []Language{
+	{Code: "AR", Name: "Arabic"},
+	{Code: "BG", Name: "Bulgarian"},
+	{Code: "CS", Name: "Czech"},
+	{Code: "DA", Name: "Danish"},
+	{Code: "DE", Name: "German", SupportsFormality: true},
+	{Code: "EL", Name: "Greek"},
+	{Code: "EN", Name: "English"},
+	{Code: "ES", Name: "Spanish", SupportsFormality: true},
+	{Code: "ET", Name: "Estonian"},
+	{Code: "FI", Name: "Finnish"},
+	{Code: "FR", Name: "French", SupportsFormality: true},
+	{Code: "HU", Name: "Hungarian"},
+	{Code: "ID", Name: "Indonesian"},
+	{Code: "IT", Name: "Italian", SupportsFormality: true},
+	{Code: "JA", Name: "Japanese", SupportsFormality: true},
+	{Code: "KO", Name: "Korean"},
+	{Code: "LT", Name: "Lithuanian"},
+	{Code: "LV", Name: "Latvian"},
+	{Code: "NB", Name: "Norwegian (Bokmål)"},
+	{Code: "NL", Name: "Dutch", SupportsFormality: true},
+	{Code: "PL", Name: "Polish", SupportsFormality: true},
+	{Code: "PT", Name: "Portuguese", SupportsFormality: true},
+	{Code: "RO", Name: "Romanian"},
+	{Code: "RU", Name: "Russian", SupportsFormality: true},
+	{Code: "SK", Name: "Slovak"},
+	{Code: "SL", Name: "Slovenian"},
+	{Code: "SV", Name: "Swedish"},
+	{Code: "TR", Name: "Turkish"},
+	{Code: "UK", Name: "Ukrainian"},
+	{Code: "ZH", Name: "Chinese"},
+}
+
+func staticLanguages(target bool) []Language {
+	languages := append([]Language(nil), staticLanguageList...)
+	if target {
+		languages = append(languages,
+			Language{Code: "EN-GB", Name: "English (British)"},
+			Language{Code: "EN-US", Name: "English (American)"},
+			Language{Code: "PT-BR", Name: "Portuguese (Brazilian)", SupportsFormality: true},
+			Language{Code: "PT-PT", Name: "Portuguese (European)", SupportsFormality: true},
+		)
+	}
+	return languages
+}
@@ -0,0 +1,39 @@
+package translation
+
+import "testing"
+
+func TestLanguageSetMatching(t *testing.T) {
+	set := LanguageSet{
+		Source: []Language{{Code: "EN"}, {Code: "DE"}},
+		Target: []Language{{Code: "DE", SupportsFormality: true}, {Code: "EN-GB"}, {Code: "EN-US"}},
+	}
+	cases := []struct {
+		code   string
+		source bool
+		target bool
+	}{
+		{"en", true, true},
+		{"pt", false, false},
+		{"EN-GB", true, true},
+		{"de-AT", true, true},
+		{"xx", false, false},
+		{"", false, false},
+	}
+	for _, tc := range cases {
+		if got := set.SupportsSource(tc.code); got != tc.source {
+			t.Errorf("source %q: expected %v got %v", tc.code, tc.source, got)
+		}
+		if _, got := set.TargetLanguage(tc.code); got != tc.target {
+			t.Errorf("target %q: expected %v got %v", tc.code, tc.target, got)
+		}
+	}
+	if lang, _ := set.TargetLanguage("de"); !lang.SupportsFormality {
+		t.Fatal("expected formality support for DE")
+	}
+	if lang, _ := set.TargetLanguage("en"); lang.Code != "EN-GB" {
+		t.Fatalf("expected EN to resolve to EN-GB, got %q", lang.Code)
+	}
+	if TargetVariant("pt") != "PT-PT" || TargetVariant("DE") != "DE" {
+		t.Fatal("unexpected default variants")
+	}
+}
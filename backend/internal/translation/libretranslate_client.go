@@ -0,0 +1,288 @@
+package translation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/models"
+)
+
+// ProviderLibreTranslate is a self-hosted LibreTranslate/Argos server.
+const ProviderLibreTranslate ProviderType = "libretranslate"
+
+// LibreTranslateModel is the catalog entry served by the self-hosted
+// provider. It has no fallbacks: documents must never leave our servers.
+var LibreTranslateModel = Model{
+	ModelDescriptor: models.ModelDescriptor{
+		Key:          "kaminskyi-private",
+		DisplayName:  "Kaminskyi Private",
+		Provider:     string(ProviderLibreTranslate),
+		Tier:         "Private",
+		PricePer1860: 0.30,
+		Currency:     "EUR",
+		Features: []string{
+			"Selbst gehostet – Dokumente verlassen unsere Server nicht",
+			"Open-Source-Modelle (Argos)",
+			"Layout bleibt erhalten (DOCX, EPUB, HTML)",
+		},
+		Options: map[string]string{
+			"priority": "standard",
+		},
+		MaxCharacters: 500000,
+		SpeedScore:    6,
+		AccuracyScore: 6,
+	},
+	Provider:          ProviderLibreTranslate,
+	Engine:            "argos",
+	SupportsFormality: false,
+	SupportsGlossary:  false,
+	SupportsDocuments: true,
+	SupportsImages:    false,
+	SupportsXMLTags:   false,
+}
+
+// LibreTranslateClient talks to a LibreTranslate-compatible HTTP API.
+type LibreTranslateClient struct {
+	httpClient *http.Client
+	apiKey     string
+	baseURL    string
+	retry      RetryPolicy
+}
+
+// LibreTranslateOptions configures the client.
+type LibreTranslateOptions struct {
+	BaseURL string
+	Timeout time.Duration
+	Retry   RetryPolicy
+}
+
+// NewLibreTranslateClient constructs the client. The API key is optional for
+// servers running without key enforcement.
+func NewLibreTranslateClient(apiKey string, opts LibreTranslateOptions) *LibreTranslateClient {
+	baseURL := strings.TrimRight(opts.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = "http://localhost:5000"
+	}
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = 2 * time.Minute
+	}
+	return &LibreTranslateClient{
+		httpClient: &http.Client{Timeout: timeout},
+		apiKey:     apiKey,
+		baseURL:    baseURL,
+		retry:      opts.Retry,
+	}
+}
+
+// LibreTranslateLanguage is a language offered by the server.
+type LibreTranslateLanguage struct {
+	Code    string   `json:"code"`
+	Name    string   `json:"name"`
+	Targets []string `json:"targets"`
+}
+
+// Translate translates a batch of texts. An empty source language lets the
+// server detect it.
+func (c *LibreTranslateClient) Translate(ctx context.Context, texts []string, sourceLang, targetLang string) ([]string, error) {
+	source := BaseLanguage(sourceLang)
+	if source == "" {
+		source = "auto"
+	}
+	payload := map[string]interface{}{
+		"q":      texts,
+		"source": source,
+		"target": BaseLanguage(targetLang),
+		"format": "text",
+	}
+	if c.apiKey != "" {
+		payload["api_key"] = c.apiKey
+	}
+	buf := &bytes.Buffer{}
+	if err := json.NewEncoder(buf).Encode(payload); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/translate", buf)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return nil, parseLibreTranslateError(resp.StatusCode, resp.Header, resp.Body)
+	}
+
+	var result struct {
+		TranslatedText []string `json:"translatedText"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if len(result.TranslatedText) != len(texts) {
+		return nil, fmt.Errorf("libretranslate: expected %d translations, got %d", len(texts), len(result.TranslatedText))
+	}
+	return result.TranslatedText, nil
+}
+
+// Languages lists the language pairs installed on the server.
+func (c *LibreTranslateClient) Languages(ctx context.Context) ([]LibreTranslateLanguage, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/languages", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return nil, parseLibreTranslateError(resp.StatusCode, resp.Header, resp.Body)
+	}
+	var languages []LibreTranslateLanguage
+	if err := json.NewDecoder(resp.Body).Decode(&languages); err != nil {
+		return nil, err
+	}
+	return languages, nil
+}
+
+// Health verifies the server is reachable.
+func (c *LibreTranslateClient) Health(ctx context.Context) error {
+	_, err := c.Languages(ctx)
+	return err
+}
+
+// LibreTranslateError represents LibreTranslate API errors. It unwraps to a
+// typed provider error such as ErrRateLimited.
+type LibreTranslateError struct {
+	StatusCode int
+	Message    string
+	RetryAfter time.Duration
+}
+
+func (e *LibreTranslateError) Error() string {
+	return fmt.Sprintf("libretranslate api error: %s", e.Message)
+}
+
+// HTTPStatus returns the response status that produced the error.
+func (e *LibreTranslateError) HTTPStatus() int {
+	return e.StatusCode
+}
+
+// Unwrap returns the typed provider error for the status.
+func (e *LibreTranslateError) Unwrap() error {
+	return statusError(e.StatusCode)
+}
+
+// do retries every request: self-hosted translation is free and has no
+// side effects.
+func (c *LibreTranslateClient) do(req *http.Request) (*http.Response, error) {
+	return sendWithRetry(c.httpClient, c.retry, req, true)
+}
+
+func parseLibreTranslateError(status int, header http.Header, body io.Reader) error {
+	var payload struct {
+		Error string `json:"error"`
+	}
+	apiErr := &LibreTranslateError{StatusCode: status, RetryAfter: parseRetryAfter(header)}
+	if err := json.NewDecoder(body).Decode(&payload); err == nil {
+		apiErr.Message = payload.Error
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(status)
+	}
+	return apiErr
+}
+
+// LibreTranslateProvider adapts LibreTranslateClient to Provider. Documents
+// are translated segment by segment and reassembled locally.
+type LibreTranslateProvider struct {
+	client     *LibreTranslateClient
+	chunkChars int
+}
+
+// NewLibreTranslateProvider wraps a client. chunkChars bounds the characters
+// sent per request and should not exceed the server's char limit.
+func NewLibreTranslateProvider(client *LibreTranslateClient, chunkChars int) *LibreTranslateProvider {
+	if chunkChars <= 0 {
+		chunkChars = 2000
+	}
+	return &LibreTranslateProvider{client: client, chunkChars: chunkChars}
+}
+
+// Type implements Provider.
+func (p *LibreTranslateProvider) Type() ProviderType {
+	return ProviderLibreTranslate
+}
+
+// Capabilities implements Provider.
+func (p *LibreTranslateProvider) Capabilities() Capabilities {
+	return Capabilities{Documents: true, Text: true}
+}
+
+// TranslateDocument implements Provider.
+func (p *LibreTranslateProvider) TranslateDocument(ctx context.Context, reader io.Reader, req DocumentRequest) ([]byte, error) {
+	return translateTextDocument(ctx, reader, req, p.chunkChars, 0, p.batch(req.SourceLang, req.TargetLang))
+}
+
+// TranslateText implements Provider.
+func (p *LibreTranslateProvider) TranslateText(ctx context.Context, text string, req TextRequest) (string, error) {
+	return TranslateLines(ctx, text, p.chunkChars, 0, p.batch(req.SourceLang, req.TargetLang))
+}
+
+// TranslateSegments implements SegmentTranslator.
+func (p *LibreTranslateProvider) TranslateSegments(ctx context.Context, segments []string, req TextRequest) ([]string, error) {
+	return TranslateSegments(ctx, segments, p.chunkChars, 0, p.batch(req.SourceLang, req.TargetLang))
+}
+
+// ProducesText implements TextOutput.
+func (p *LibreTranslateProvider) ProducesText(fileName string) bool {
+	return !CanReassemble(fileName)
+}
+
+// Languages implements LanguageSource with the pairs installed on the server.
+func (p *LibreTranslateProvider) Languages(ctx context.Context) (*LanguageSet, error) {
+	installed, err := p.client.Languages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	set := &LanguageSet{}
+	names := make(map[string]string, len(installed))
+	targets := make(map[string]bool)
+	for _, l := range installed {
+		code := strings.ToUpper(l.Code)
+		names[code] = l.Name
+		set.Source = append(set.Source, Language{Code: code, Name: l.Name})
+		for _, t := range l.Targets {
+			targets[strings.ToUpper(t)] = true
+		}
+	}
+	for _, l := range set.Source {
+		if targets[l.Code] {
+			set.Target = append(set.Target, Language{Code: l.Code, Name: names[l.Code]})
+		}
+	}
+	return set, nil
+}
+
+// Health implements Provider.
+func (p *LibreTranslateProvider) Health(ctx context.Context) error {
+	return p.client.Health(ctx)
+}
+
+func (p *LibreTranslateProvider) batch(sourceLang, targetLang string) BatchTranslateFunc {
+	return func(ctx context.Context, batch SegmentBatch) ([]string, error) {
+		return p.client.Translate(ctx, batch.Segments, sourceLang, targetLang)
+	}
+}
@@ -0,0 +1,168 @@
+package translation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// LLMClient talks to any OpenAI-compatible chat completions endpoint,
+// e.g. OpenAI itself or a local llama.cpp/vLLM server.
+type LLMClient struct {
+	httpClient *http.Client
+	apiKey     string
+	baseURL    string
+	model      string
+	retry      RetryPolicy
+}
+
+// LLMOptions configure the LLM client.
+type LLMOptions struct {
+	BaseURL string
+	Model   string
+	Timeout time.Duration
+	Retry   RetryPolicy
+}
+
+// NewLLMClient constructs the client.
+func NewLLMClient(apiKey string, opts LLMOptions) *LLMClient {
+	baseURL := strings.TrimRight(opts.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = "https://api.openai.com/v1"
+	}
+	model := opts.Model
+	if model == "" {
+		model = "gpt-4o-mini"
+	}
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = 2 * time.Minute
+	}
+	return &LLMClient{
+		httpClient: &http.Client{Timeout: timeout},
+		apiKey:     apiKey,
+		baseURL:    baseURL,
+		model:      model,
+		retry:      opts.Retry,
+	}
+}
+
+// ChatMessage is a single chat completion message.
+type ChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Complete sends messages and returns the first choice's content.
+func (c *LLMClient) Complete(ctx context.Context, messages []ChatMessage) (string, error) {
+	payload := map[string]interface{}{
+		"model":       c.model,
+		"messages":    messages,
+		"temperature": 0,
+	}
+	buf := &bytes.Buffer{}
+	if err := json.NewEncoder(buf).Encode(payload); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/chat/completions", buf)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return "", parseLLMError(resp.StatusCode, resp.Header, resp.Body)
+	}
+
+	var result struct {
+		Choices []struct {
+			Message ChatMessage `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", err
+	}
+	if len(result.Choices) == 0 {
+		return "", errors.New("llm: empty completion")
+	}
+	return result.Choices[0].Message.Content, nil
+}
+
+// Health lists models to verify the endpoint is reachable.
+func (c *LLMClient) Health(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/models", nil)
+	if err != nil {
+		return err
+	}
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return parseLLMError(resp.StatusCode, resp.Header, resp.Body)
+	}
+	return nil
+}
+
+// LLMError represents chat completion API errors. It unwraps to a typed
+// provider error such as ErrRateLimited.
+type LLMError struct {
+	StatusCode int
+	Message    string
+	RetryAfter time.Duration
+}
+
+func (e *LLMError) Error() string {
+	return fmt.Sprintf("llm api error: %s", e.Message)
+}
+
+// HTTPStatus returns the response status that produced the error.
+func (e *LLMError) HTTPStatus() int {
+	return e.StatusCode
+}
+
+// Unwrap returns the typed provider error for the status.
+func (e *LLMError) Unwrap() error {
+	return statusError(e.StatusCode)
+}
+
+// do retries completions too: a chat completion has no side effects
+// besides token usage.
+func (c *LLMClient) do(req *http.Request) (*http.Response, error) {
+	return sendWithRetry(c.httpClient, c.retry, req, true)
+}
+
+func parseLLMError(status int, header http.Header, body io.Reader) error {
+	var payload struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	apiErr := &LLMError{StatusCode: status, RetryAfter: parseRetryAfter(header)}
+	if err := json.NewDecoder(body).Decode(&payload); err == nil {
+		apiErr.Message = payload.Error.Message
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(status)
+	}
+	return apiErr
+}
@@ -0,0 +1,327 @@
+package translation
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/models"
+)
+
+// ProviderLLM is an OpenAI-compatible chat completions backend.
+const ProviderLLM ProviderType = "llm"
+
+// LLM engines select the prompt template used for a model tier.
+const (
+	LLMEngineStandard  = "llm-standard"
+	LLMEngineContext   = "llm-context"
+	LLMEngineMultiPass = "llm-multipass"
+)
+
+// LLMModels are the catalog entries served by the LLM provider.
+var LLMModels = []Model{
+	{
+		ModelDescriptor: models.ModelDescriptor{
+			Key:          "kaminskyi-context",
+			DisplayName:  "Kaminskyi Context",
+			Provider:     string(ProviderLLM),
+			Tier:         "Context",
+			PricePer1860: 0.60,
+			Currency:     "EUR",
+			Features: []string{
+				"Kontextbewusste Übersetzung",
+				"Konsistente Terminologie über Abschnitte",
+				"Platzhalter bleiben erhalten",
+			},
+			Options: map[string]string{
+				"formality": "default",
+				"priority":  "standard",
+			},
+			MaxCharacters: 250000,
+			SpeedScore:    5,
+			AccuracyScore: 9,
+		},
+		Provider:          ProviderLLM,
+		Engine:            LLMEngineContext,
+		SupportsFormality: true,
+		SupportsGlossary:  false,
+		SupportsDocuments: true,
+		SupportsImages:    false,
+		SupportsXMLTags:   false,
+	},
+	{
+		ModelDescriptor: models.ModelDescriptor{
+			Key:          "kaminskyi-multipass",
+			DisplayName:  "Kaminskyi Multipass",
+			Provider:     string(ProviderLLM),
+			Tier:         "Multipass",
+			PricePer1860: 0.90,
+			Currency:     "EUR",
+			Features: []string{
+				"Kontextbewusste Übersetzung",
+				"Fehlererkennung in mehreren Durchläufen",
+				"Stilistische Nachbearbeitung",
+			},
+			Options: map[string]string{
+				"formality": "default",
+				"priority":  "priority",
+				"passes":    "2",
+			},
+			MaxCharacters: 200000,
+			SpeedScore:    3,
+			AccuracyScore: 10,
+		},
+		Provider:          ProviderLLM,
+		Engine:            LLMEngineMultiPass,
+		SupportsFormality: true,
+		SupportsGlossary:  false,
+		SupportsDocuments: true,
+		SupportsImages:    false,
+		SupportsXMLTags:   false,
+	},
+}
+
+var llmPrompts = map[string]*template.Template{
+	LLMEngineStandard: template.Must(template.New(LLMEngineStandard).Parse(
+		`You are a professional translator. Translate each segment {{if .SourceLang}}from {{.SourceLang}} {{end}}into {{.TargetLang}}.{{if .Formality}} {{.Formality}}{{end}}
+Every segment starts with a marker such as <<1>> and runs until the next marker; it may span several lines. Answer with every segment translated, prefixed with the same marker, keeping its line breaks, and nothing else.
+Keep placeholders such as ⟦1⟧, markup, numbers and URLs unchanged.`)),
+	LLMEngineContext: template.Must(template.New(LLMEngineContext).Parse(
+		`You are a senior translator working on a longer document. Translate each segment {{if .SourceLang}}from {{.SourceLang}} {{end}}into {{.TargetLang}}.{{if .Formality}} {{.Formality}}{{end}}
+Use the preceding context, if given, to keep terminology, names and tone consistent, but do not translate it again.
+Every segment starts with a marker such as <<1>> and runs until the next marker; it may span several lines. Answer with every segment translated, prefixed with the same marker, keeping its line breaks, and nothing else.
+Keep placeholders such as ⟦1⟧, markup, numbers and URLs unchanged.`)),
+}
+
+var llmReviewPrompt = template.Must(template.New("review").Parse(
+	`You are a meticulous reviewer of translations into {{.TargetLang}}.{{if .Formality}} {{.Formality}}{{end}}
+You receive source segments and a draft translation with matching markers such as <<1>>. Fix mistranslations, omissions, grammar and inconsistent terminology. Keep placeholders such as ⟦1⟧ unchanged.
+Answer with every segment, prefixed with the same marker, keeping its line breaks, and nothing else.`))
+
+// llmMarkerPattern finds segment markers at the start of a line. A segment
+// runs until the next marker, so targets keep their line breaks.
+var llmMarkerPattern = regexp.MustCompile(`(?m)^[ \t]*<<(\d+)>>[ \t]?`)
+
+// LLMProviderOptions configure chunking for the LLM provider.
+type LLMProviderOptions struct {
+	ChunkChars      int
+	ContextSegments int
+}
+
+// LLMProvider translates text with an LLM, chunk by chunk.
+type LLMProvider struct {
+	client          *LLMClient
+	chunkChars      int
+	contextSegments int
+}
+
+// NewLLMProvider wraps an LLM client.
+func NewLLMProvider(client *LLMClient, opts LLMProviderOptions) *LLMProvider {
+	chunkChars := opts.ChunkChars
+	if chunkChars <= 0 {
+		chunkChars = 3000
+	}
+	return &LLMProvider{client: client, chunkChars: chunkChars, contextSegments: opts.ContextSegments}
+}
+
+// Type implements Provider.
+func (p *LLMProvider) Type() ProviderType {
+	return ProviderLLM
+}
+
+// Capabilities implements Provider.
+func (p *LLMProvider) Capabilities() Capabilities {
+	return Capabilities{Documents: true, Text: true, Formality: true}
+}
+
+// TranslateDocument translates DOCX, EPUB, HTML and text files in place and
+// returns the extracted text translated for every other format.
+func (p *LLMProvider) TranslateDocument(ctx context.Context, reader io.Reader, req DocumentRequest) ([]byte, error) {
+	return translateTextDocument(ctx, reader, req, p.chunkChars, p.contextSegmentsFor(req.Engine), func(ctx context.Context, batch SegmentBatch) ([]string, error) {
+		return p.translateBatch(ctx, batch, TextRequest{
+			SourceLang: req.SourceLang,
+			TargetLang: req.TargetLang,
+			Engine:     req.Engine,
+			Formality:  req.Formality,
+			Passes:     req.Passes,
+		})
+	})
+}
+
+// ProducesText implements TextOutput.
+func (p *LLMProvider) ProducesText(fileName string) bool {
+	return !CanReassemble(fileName)
+}
+
+// TranslateText implements Provider.
+func (p *LLMProvider) TranslateText(ctx context.Context, text string, req TextRequest) (string, error) {
+	return TranslateLines(ctx, text, p.chunkChars, p.contextSegmentsFor(req.Engine), func(ctx context.Context, batch SegmentBatch) ([]string, error) {
+		return p.translateBatch(ctx, batch, req)
+	})
+}
+
+// TranslateSegments implements SegmentTranslator.
+func (p *LLMProvider) TranslateSegments(ctx context.Context, segments []string, req TextRequest) ([]string, error) {
+	return TranslateSegments(ctx, segments, p.chunkChars, p.contextSegmentsFor(req.Engine), func(ctx context.Context, batch SegmentBatch) ([]string, error) {
+		return p.translateBatch(ctx, batch, req)
+	})
+}
+
+// Languages implements LanguageSource with the static list.
+func (p *LLMProvider) Languages(ctx context.Context) (*LanguageSet, error) {
+	set := StaticLanguages
+	return &set, nil
+}
+
+// Health implements Provider.
+func (p *LLMProvider) Health(ctx context.Context) error {
+	return p.client.Health(ctx)
+}
+
+func (p *LLMProvider) contextSegmentsFor(engine string) int {
+	if engine == LLMEngineStandard || engine == "" {
+		return 0
+	}
+	return p.contextSegments
+}
+
+type llmPromptData struct {
+	SourceLang string
+	TargetLang string
+	Formality  string
+}
+
+func (p *LLMProvider) translateBatch(ctx context.Context, batch SegmentBatch, req TextRequest) ([]string, error) {
+	tmpl, ok := llmPrompts[req.Engine]
+	if !ok {
+		tmpl = llmPrompts[LLMEngineContext]
+	}
+	data := llmPromptData{
+		SourceLang: strings.ToUpper(req.SourceLang),
+		TargetLang: strings.ToUpper(req.TargetLang),
+		Formality:  formalityInstruction(req.Formality),
+	}
+	system, err := renderPrompt(tmpl, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var user strings.Builder
+	if len(batch.PrecedingSource) > 0 {
+		user.WriteString("Preceding context (already translated, for reference only):\n")
+		for i, src := range batch.PrecedingSource {
+			fmt.Fprintf(&user, "%s => %s\n", src, batch.PrecedingTarget[i])
+		}
+		user.WriteString("\nSegments to translate:\n")
+	}
+	user.WriteString(markSegments(batch.Segments))
+
+	targets, _, err := p.complete(ctx, system, user.String(), len(batch.Segments))
+	if err != nil {
+		return nil, err
+	}
+	if targets == nil {
+		// The model did not return every marker; fall back to one segment
+		// per request so alignment is guaranteed.
+		targets = make([]string, len(batch.Segments))
+		for i, seg := range batch.Segments {
+			single, raw, err := p.complete(ctx, system, markSegments([]string{seg}), 1)
+			if err != nil {
+				return nil, err
+			}
+			if single == nil {
+				single = []string{strings.TrimSpace(raw)}
+			}
+			targets[i] = single[0]
+		}
+	}
+
+	passes := req.Passes
+	if req.Engine != LLMEngineMultiPass {
+		passes = 1
+	}
+	for pass := 1; pass < passes; pass++ {
+		review, err := renderPrompt(llmReviewPrompt, data)
+		if err != nil {
+			return nil, err
+		}
+		prompt := "Source:\n" + markSegments(batch.Segments) + "\nDraft:\n" + markSegments(targets)
+		improved, _, err := p.complete(ctx, review, prompt, len(batch.Segments))
+		if err != nil {
+			return nil, err
+		}
+		if improved == nil {
+			// Keep the draft when the review answer cannot be aligned.
+			break
+		}
+		targets = improved
+	}
+	return targets, nil
+}
+
+// complete runs one prompt and parses the marked lines of the answer. The
+// parsed targets are nil when not every marker was returned.
+func (p *LLMProvider) complete(ctx context.Context, system, user string, count int) ([]string, string, error) {
+	content, err := p.client.Complete(ctx, []ChatMessage{
+		{Role: "system", Content: system},
+		{Role: "user", Content: user},
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	targets, ok := parseMarkedSegments(content, count)
+	if !ok {
+		return nil, content, nil
+	}
+	return targets, content, nil
+}
+
+func markSegments(segments []string) string {
+	var builder strings.Builder
+	for i, seg := range segments {
+		fmt.Fprintf(&builder, "<<%d>> %s\n", i+1, seg)
+	}
+	return builder.String()
+}
+
+func parseMarkedSegments(content string, count int) ([]string, bool) {
+	targets := make([]string, count)
+	found := 0
+	matches := llmMarkerPattern.FindAllStringSubmatchIndex(content, -1)
+	for i, match := range matches {
+		idx, err := strconv.Atoi(content[match[2]:match[3]])
+		if err != nil || idx < 1 || idx > count || targets[idx-1] != "" {
+			continue
+		}
+		end := len(content)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		targets[idx-1] = strings.TrimSpace(content[match[1]:end])
+		found++
+	}
+	return targets, found == count
+}
+
+func renderPrompt(tmpl *template.Template, data llmPromptData) (string, error) {
+	var builder strings.Builder
+	if err := tmpl.Execute(&builder, data); err != nil {
+		return "", err
+	}
+	return builder.String(), nil
+}
+
+func formalityInstruction(formality string) string {
+	switch formality {
+	case "more", "prefer_more":
+		return "Use a formal register."
+	case "less", "prefer_less":
+		return "Use an informal register."
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,106 @@
+package translation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// llmServer answers chat completions with reply and records the prompts.
+func llmServer(t *testing.T, reply func(system, user string) string) (*LLMProvider, *[]ChatMessage) {
+	t.Helper()
+	var received []ChatMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Messages []ChatMessage `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || len(payload.Messages) != 2 {
+			t.Errorf("unexpected request: %v", err)
+			return
+		}
+		received = append(received, payload.Messages...)
+		content := reply(payload.Messages[0].Content, payload.Messages[1].Content)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"choices": []map[string]interface{}{{"message": ChatMessage{Role: "assistant", Content: content}}},
+		})
+	}))
+	t.Cleanup(server.Close)
+	client := NewLLMClient("key", LLMOptions{BaseURL: server.URL})
+	return NewLLMProvider(client, LLMProviderOptions{ContextSegments: 2}), &received
+}
+
+func TestParseMarkedSegmentsKeepsLineBreaks(t *testing.T) {
+	content := "<<2>> Zweite\n<<1>> Erste Zeile\nzweite Zeile\n\n<<3>> Dritte\n"
+	targets, ok := parseMarkedSegments(content, 3)
+	if !ok || !reflect.DeepEqual(targets, []string{"Erste Zeile\nzweite Zeile", "Zweite", "Dritte"}) {
+		t.Fatalf("unexpected targets %q (%v)", targets, ok)
+	}
+	if _, ok := parseMarkedSegments("<<1>> Nur eine", 2); ok {
+		t.Fatal("expected a missing marker to be reported")
+	}
+}
+
+func TestLLMTranslateSegmentsKeepsMultilineSegments(t *testing.T) {
+	provider, received := llmServer(t, func(_, user string) string {
+		return strings.ToUpper(user)
+	})
+	targets, err := provider.TranslateSegments(context.Background(), []string{"Line one\nline two", "Single"}, TextRequest{
+		TargetLang: "de",
+		Engine:     LLMEngineStandard,
+		Formality:  "more",
+	})
+	if err != nil {
+		t.Fatalf("translate: %v", err)
+	}
+	if !reflect.DeepEqual(targets, []string{"LINE ONE\nLINE TWO", "SINGLE"}) {
+		t.Fatalf("unexpected targets %q", targets)
+	}
+	if len(*received) != 2 {
+		t.Fatalf("expected one request, got %d messages", len(*received))
+	}
+	system := (*received)[0].Content
+	if !strings.Contains(system, "into DE.") || !strings.Contains(system, "Use a formal register.") {
+		t.Fatalf("unexpected system prompt %q", system)
+	}
+}
+
+func TestLLMFallsBackToSingleSegmentsWhenMarkersAreLost(t *testing.T) {
+	calls := 0
+	provider, _ := llmServer(t, func(_, user string) string {
+		calls++
+		if strings.Contains(user, "<<2>>") {
+			return "<<1>> EINS"
+		}
+		return strings.TrimPrefix(strings.TrimSpace(user), "<<1>> ") + "!"
+	})
+	targets, err := provider.TranslateSegments(context.Background(), []string{"one", "two"}, TextRequest{TargetLang: "DE", Engine: LLMEngineStandard})
+	if err != nil {
+		t.Fatalf("translate: %v", err)
+	}
+	if calls != 3 || !reflect.DeepEqual(targets, []string{"one!", "two!"}) {
+		t.Fatalf("unexpected targets %q after %d calls", targets, calls)
+	}
+}
+
+func TestLLMMultiPassReviewsDraft(t *testing.T) {
+	provider, received := llmServer(t, func(system, user string) string {
+		if strings.Contains(system, "reviewer") {
+			if !strings.Contains(user, "Draft:\n<<1>> Hallo Welt") {
+				t.Errorf("review prompt lacks the draft: %q", user)
+			}
+			return "<<1>> Hallo, Welt"
+		}
+		return "<<1>> Hallo Welt"
+	})
+	targets, err := provider.TranslateSegments(context.Background(), []string{"Hello world"}, TextRequest{TargetLang: "DE", Engine: LLMEngineMultiPass, Passes: 2})
+	if err != nil {
+		t.Fatalf("translate: %v", err)
+	}
+	if !reflect.DeepEqual(targets, []string{"Hallo, Welt"}) || len(*received) != 4 {
+		t.Fatalf("unexpected targets %q after %d messages", targets, len(*received))
+	}
+}
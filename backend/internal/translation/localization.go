@@ -0,0 +1,306 @@
+package translation
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"html"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// localizationFormats are software string files. Document providers do not
+// read them, so they are always translated value by value.
+var localizationFormats = map[string]bool{
+	".json": true, ".po": true, ".pot": true, ".xml": true,
+	".strings": true, ".xcstrings": true, ".yaml": true, ".yml": true,
+}
+
+// IsLocalizationFile reports whether fileName is a software localization
+// file whose values are translated and whose keys are kept.
+func IsLocalizationFile(fileName string) bool {
+	return localizationFormats[strings.ToLower(filepath.Ext(fileName))]
+}
+
+// rewriteJSON translates the string values of i18n JSON, nested or flat.
+// Keys, numbers, formatting and key order are kept.
+func rewriteJSON(data []byte, replace func(string) string) ([]byte, error) {
+	if !json.Valid(data) {
+		return nil, errors.New("invalid json")
+	}
+	text := string(data)
+	var builder strings.Builder
+	for i := 0; i < len(text); {
+		if text[i] != '"' {
+			builder.WriteByte(text[i])
+			i++
+			continue
+		}
+		end := jsonStringEnd(text, i)
+		literal := text[i:end]
+		i = end
+		if strings.HasPrefix(strings.TrimLeft(text[end:], " \t\r\n"), ":") {
+			builder.WriteString(literal)
+			continue
+		}
+		var value string
+		if err := json.Unmarshal([]byte(literal), &value); err != nil {
+			return nil, err
+		}
+		translated := replaceTrimmed(value, replace)
+		if translated == value {
+			builder.WriteString(literal)
+			continue
+		}
+		builder.WriteString(encodeJSONString(translated))
+	}
+	return []byte(builder.String()), nil
+}
+
+// jsonStringEnd returns the offset after the string literal at start.
+func jsonStringEnd(text string, start int) int {
+	for i := start + 1; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return len(text)
+}
+
+func encodeJSONString(value string) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(value)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+var (
+	androidElementPattern        = regexp.MustCompile(`(?s)<(string|item)(\s[^>]*)?>(.*?)</(?:string|item)>`)
+	androidUntranslatablePattern = regexp.MustCompile(`translatable\s*=\s*"false"`)
+	androidEscaper               = strings.NewReplacer(`\`, `\\`, "\n", `\n`, "\t", `\t`)
+)
+
+// IsAndroidResource reports whether data is an Android resource file, whose
+// root element is <resources>. Other XML is not a localization file.
+func IsAndroidResource(data []byte) bool {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return false
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local == "resources"
+		}
+	}
+}
+
+// rewriteAndroidStrings translates the <string> elements and the <item>s of
+// string arrays and plurals in Android resources. Untranslatable strings and
+// resource references are kept, as is inline markup inside a value.
+func rewriteAndroidStrings(data []byte, replace func(string) string) ([]byte, error) {
+	if !IsAndroidResource(data) {
+		return nil, errors.New("only Android string resources are supported as xml")
+	}
+	return androidElementPattern.ReplaceAllFunc(data, func(element []byte) []byte {
+		loc := androidElementPattern.FindSubmatchIndex(element)
+		attrs := ""
+		if loc[4] >= 0 {
+			attrs = string(element[loc[4]:loc[5]])
+		}
+		inner := string(element[loc[6]:loc[7]])
+		trimmed := strings.TrimSpace(inner)
+		if androidUntranslatablePattern.MatchString(attrs) || trimmed == "" ||
+			strings.HasPrefix(trimmed, "@") || strings.HasPrefix(trimmed, "?") || strings.HasPrefix(trimmed, "<![CDATA[") {
+			return element
+		}
+		var value string
+		if strings.Contains(inner, "<") {
+			// Values with markup are translated as markup; the tags are
+			// protected as placeholders.
+			value = replaceTrimmed(inner, replace)
+		} else {
+			value = replaceTrimmed(inner, func(s string) string {
+				return androidEscape(replace(androidUnescape(html.UnescapeString(s))))
+			})
+		}
+		return []byte(string(element[:loc[6]]) + value + string(element[loc[7]:]))
+	}), nil
+}
+
+func androidUnescape(s string) string {
+	var builder strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			builder.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			builder.WriteByte('\n')
+		case 't':
+			builder.WriteByte('\t')
+		default:
+			builder.WriteByte(s[i])
+		}
+	}
+	value := builder.String()
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		// Double quotes around a value only preserve its whitespace.
+		value = value[1 : len(value)-1]
+	}
+	return value
+}
+
+func androidEscape(s string) string {
+	escaped := androidEscaper.Replace(html.EscapeString(s))
+	// html.EscapeString turns quotes into entities; Android expects them
+	// backslash-escaped.
+	escaped = strings.NewReplacer("&#39;", `\'`, "&#34;", `\"`).Replace(escaped)
+	if strings.HasPrefix(escaped, "@") || strings.HasPrefix(escaped, "?") {
+		escaped = `\` + escaped
+	}
+	return escaped
+}
+
+// rewriteAppleStrings translates the values of "key" = "value"; pairs in
+// Apple .strings files. Comments and keys are kept; UTF-16 files stay UTF-16.
+func rewriteAppleStrings(data []byte, replace func(string) string) ([]byte, error) {
+	text, encode, err := decodeStringsFile(data)
+	if err != nil {
+		return nil, err
+	}
+	var builder strings.Builder
+	expectValue := false
+	for i := 0; i < len(text); {
+		rest := text[i:]
+		switch {
+		case strings.HasPrefix(rest, "/*"):
+			end := strings.Index(rest[2:], "*/")
+			if end < 0 {
+				return nil, errors.New("invalid .strings: unterminated comment")
+			}
+			builder.WriteString(rest[:end+4])
+			i += end + 4
+		case strings.HasPrefix(rest, "//"):
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				end = len(rest)
+			}
+			builder.WriteString(rest[:end])
+			i += end
+		case rest[0] == '"':
+			end := jsonStringEnd(rest, 0)
+			literal := rest[:end]
+			i += end
+			if !expectValue {
+				builder.WriteString(literal)
+				continue
+			}
+			expectValue = false
+			value := unescapeAppleString(literal[1 : len(literal)-1])
+			translated := replaceTrimmed(value, replace)
+			if translated == value {
+				builder.WriteString(literal)
+				continue
+			}
+			builder.WriteString(`"` + escapeAppleString(translated) + `"`)
+		case rest[0] == '=':
+			expectValue = true
+			builder.WriteByte('=')
+			i++
+		default:
+			if rest[0] == ';' {
+				expectValue = false
+			}
+			builder.WriteByte(rest[0])
+			i++
+		}
+	}
+	return encode(builder.String()), nil
+}
+
+// decodeStringsFile returns the text of a UTF-8 or UTF-16 .strings file and
+// a function encoding text back the same way.
+func decodeStringsFile(data []byte) (string, func(string) []byte, error) {
+	var order binary.ByteOrder
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}):
+		order = binary.LittleEndian
+	case bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		order = binary.BigEndian
+	default:
+		if !utf8.Valid(data) {
+			return "", nil, errors.New("invalid .strings: not UTF-8 or UTF-16")
+		}
+		return string(data), func(s string) []byte { return []byte(s) }, nil
+	}
+	body := data[2:]
+	if len(body)%2 != 0 {
+		return "", nil, errors.New("invalid .strings: odd UTF-16 length")
+	}
+	units := make([]uint16, len(body)/2)
+	for i := range units {
+		units[i] = order.Uint16(body[2*i:])
+	}
+	encode := func(s string) []byte {
+		encoded := utf16.Encode([]rune(s))
+		out := make([]byte, 2+2*len(encoded))
+		copy(out, data[:2])
+		for i, unit := range encoded {
+			order.PutUint16(out[2+2*i:], unit)
+		}
+		return out
+	}
+	return string(utf16.Decode(units)), encode, nil
+}
+
+func unescapeAppleString(s string) string {
+	var builder strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			builder.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			builder.WriteByte('\n')
+		case 't':
+			builder.WriteByte('\t')
+		case 'r':
+			builder.WriteByte('\r')
+		case 'U', 'u':
+			if i+4 < len(s) {
+				if code, err := strconv.ParseUint(s[i+1:i+5], 16, 32); err == nil {
+					builder.WriteRune(rune(code))
+					i += 4
+					continue
+				}
+			}
+			builder.WriteByte('\\')
+			builder.WriteByte(s[i])
+		default:
+			builder.WriteByte(s[i])
+		}
+	}
+	return builder.String()
+}
+
+var appleStringEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\r", `\r`)
+
+func escapeAppleString(s string) string {
+	return appleStringEscaper.Replace(s)
+}
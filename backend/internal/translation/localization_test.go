@@ -0,0 +1,207 @@
+package translation
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func reassembleUpper(t *testing.T, fileName, targetLang, input string) string {
+	t.Helper()
+	out, err := ReassembleDocument(context.Background(), fileName, targetLang, []byte(input), upperSegments)
+	if err != nil {
+		t.Fatalf("reassemble %s: %v", fileName, err)
+	}
+	return string(out)
+}
+
+func TestReassembleJSONKeepsKeys(t *testing.T) {
+	input := "{\n  \"nav\": {\"home\": \"Home\", \"count\": 3, \"items\": [\"one\", \"two\"]},\n  \"quote\": \"say \\\"hi\\\"\"\n}"
+	expected := "{\n  \"nav\": {\"home\": \"HOME\", \"count\": 3, \"items\": [\"ONE\", \"TWO\"]},\n  \"quote\": \"SAY \\\"HI\\\"\"\n}"
+	if out := reassembleUpper(t, "en.json", "DE", input); out != expected {
+		t.Fatalf("expected %q got %q", expected, out)
+	}
+}
+
+func TestReassembleGettext(t *testing.T) {
+	input := `msgid ""
+msgstr ""
+"Language: de\n"
+
+# Greeting
+#, fuzzy
+msgctxt "menu"
+msgid "Hello"
+msgstr ""
+
+msgid "One file"
+msgid_plural "%d files"
+msgstr[0] ""
+msgstr[1] ""
+msgstr[2] ""
+
+msgid ""
+"Line one\n"
+"line two"
+msgstr ""
+`
+	expected := `msgid ""
+msgstr ""
+"Language: de\n"
+
+# Greeting
+#, fuzzy
+msgctxt "menu"
+msgid "Hello"
+msgstr "HELLO"
+
+msgid "One file"
+msgid_plural "%d files"
+msgstr[0] "ONE FILE"
+msgstr[1] "%D FILES"
+msgstr[2] "%D FILES"
+
+msgid ""
+"Line one\n"
+"line two"
+msgstr ""
+"LINE ONE\n"
+"LINE TWO"
+`
+	out := reassembleUpper(t, "de.po", "DE", input)
+	if out != expected {
+		t.Fatalf("expected %q got %q", expected, out)
+	}
+	source, _ := DocumentSegments("de.po", []byte(input))
+	targets, err := TranslatedSegments("de.po", "DE", []byte(out))
+	if err != nil {
+		t.Fatalf("targets: %v", err)
+	}
+	if len(source) != 4 || !reflect.DeepEqual(targets, []string{"HELLO", "ONE FILE", "%D FILES", "LINE ONE\nLINE TWO"}) {
+		t.Fatalf("unexpected segments %q / %q", source, targets)
+	}
+}
+
+func TestReassembleAndroidStrings(t *testing.T) {
+	input := `<resources>
+    <!-- Main screen -->
+    <string name="app_name" translatable="false">Acme</string>
+    <string name="greeting">Don\'t panic &amp; relax</string>
+    <string name="link">@string/greeting</string>
+    <plurals name="files"><item quantity="one">%d file</item><item quantity="other">%d files</item></plurals>
+</resources>`
+	expected := `<resources>
+    <!-- Main screen -->
+    <string name="app_name" translatable="false">Acme</string>
+    <string name="greeting">DON\'T PANIC &amp; RELAX</string>
+    <string name="link">@string/greeting</string>
+    <plurals name="files"><item quantity="one">%D FILE</item><item quantity="other">%D FILES</item></plurals>
+</resources>`
+	if out := reassembleUpper(t, "strings.xml", "DE", input); out != expected {
+		t.Fatalf("expected %q got %q", expected, out)
+	}
+}
+
+func TestAndroidStringsRequireResourcesRoot(t *testing.T) {
+	if !IsAndroidResource([]byte("<?xml version=\"1.0\"?>\n<!-- app -->\n<resources><string name=\"a\">A</string></resources>")) {
+		t.Fatal("expected an Android resource file")
+	}
+	if _, err := DocumentSegments("pom.xml", []byte(`<project><name>Acme</name><string>x</string></project>`)); err == nil {
+		t.Fatal("expected other xml to be rejected")
+	}
+}
+
+func TestReassembleAppleStrings(t *testing.T) {
+	input := "/* Title \"x\" = \"y\"; */\n\"title\" = \"Welcome\";\n// note\n\"quote\" = \"Say \\\"hi\\\"\\n\";\n"
+	expected := "/* Title \"x\" = \"y\"; */\n\"title\" = \"WELCOME\";\n// note\n\"quote\" = \"SAY \\\"HI\\\"\\n\";\n"
+	if out := reassembleUpper(t, "Localizable.strings", "DE", input); out != expected {
+		t.Fatalf("expected %q got %q", expected, out)
+	}
+	utf16 := []byte{0xFF, 0xFE, '"', 0, 'k', 0, '"', 0, '=', 0, '"', 0, 'v', 0, '"', 0, ';', 0}
+	out, err := ReassembleDocument(context.Background(), "a.strings", "DE", utf16, upperSegments)
+	if err != nil || string(out) != string([]byte{0xFF, 0xFE, '"', 0, 'k', 0, '"', 0, '=', 0, '"', 0, 'V', 0, '"', 0, ';', 0}) {
+		t.Fatalf("unexpected utf-16 output %v (%v)", out, err)
+	}
+}
+
+func TestReassembleStringCatalog(t *testing.T) {
+	input := `{
+  "sourceLanguage" : "en",
+  "strings" : {
+    "Cancel" : {},
+    "files" : {
+      "localizations" : {
+        "en" : {"variations" : {"plural" : {
+          "one" : {"stringUnit" : {"state" : "translated", "value" : "%lld file"}},
+          "other" : {"stringUnit" : {"state" : "translated", "value" : "%lld files"}}
+        }}}
+      }
+    },
+    "id" : {"shouldTranslate" : false}
+  },
+  "version" : "1.0"
+}`
+	out := reassembleUpper(t, "Localizable.xcstrings", "PT-BR", input)
+	for _, want := range []string{`"pt-BR": {`, `"value": "CANCEL"`, `"value": "%LLD FILE"`, `"value": "%lld files"`, `"shouldTranslate": false`} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %s in %s", want, out)
+		}
+	}
+	targets, err := TranslatedSegments("Localizable.xcstrings", "PT-BR", []byte(out))
+	if err != nil {
+		t.Fatalf("targets: %v", err)
+	}
+	if !reflect.DeepEqual(targets, []string{"CANCEL", "%LLD FILE", "%LLD FILES"}) {
+		t.Fatalf("unexpected targets %q", targets)
+	}
+}
+
+func TestReassembleYAML(t *testing.T) {
+	input := `# Locale
+en:
+  title: Welcome home # shown on top
+  enabled: true
+  count: 3
+  quoted: "Hello: world"
+  single: 'It''s here'
+  anchor: &base Base
+  items:
+    - first
+    - second
+  body: |
+    Line one
+    line two
+
+  after: done
+`
+	expected := `# Locale
+en:
+  title: WELCOME HOME # shown on top
+  enabled: true
+  count: 3
+  quoted: "HELLO: WORLD"
+  single: 'IT''S HERE'
+  anchor: &base Base
+  items:
+    - FIRST
+    - SECOND
+  body: |
+    LINE ONE
+    LINE TWO
+
+  after: DONE
+`
+	if out := reassembleUpper(t, "en.yml", "DE", input); out != expected {
+		t.Fatalf("expected %q got %q", expected, out)
+	}
+}
+
+func TestYAMLQuotesValuesThatNeedIt(t *testing.T) {
+	out, err := ReassembleDocument(context.Background(), "de.yaml", "DE", []byte("greeting: Hello\n"), func(_ context.Context, segments []string) ([]string, error) {
+		return []string{"Hinweis: hallo"}, nil
+	})
+	if err != nil || string(out) != "greeting: \"Hinweis: hallo\"\n" {
+		t.Fatalf("unexpected output %q (%v)", out, err)
+	}
+}
@@ -0,0 +1,190 @@
+package translation
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	markdownQuotePattern      = regexp.MustCompile(`^[ \t]*(?:>[ \t]?)*`)
+	markdownFencePattern      = regexp.MustCompile("^[ \t]*(```+|~~~+)")
+	markdownHeadingPattern    = regexp.MustCompile(`^([ \t]*#{1,6}[ \t]+)(.*?)([ \t]+#+)?[ \t]*$`)
+	markdownListPattern       = regexp.MustCompile(`^([ \t]*(?:[-*+]|\d{1,9}[.)])(?:[ \t]+\[[ xX]\])?[ \t]+)(.*)$`)
+	markdownBreakPattern      = regexp.MustCompile(`^[ \t]{0,3}(?:(?:-[ \t]*){3,}|(?:\*[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	markdownUnderlinePattern  = regexp.MustCompile(`^[ \t]{0,3}(?:=+|-+)[ \t]*$`)
+	markdownDefinitionPattern = regexp.MustCompile(`^[ \t]{0,3}\[[^\]]+\]:[ \t]*\S`)
+	markdownHTMLPattern       = regexp.MustCompile(`^[ \t]{0,3}<(?:[A-Za-z][A-Za-z0-9-]*[\s/>]|/[A-Za-z]|!--)`)
+	markdownDelimiterPattern  = regexp.MustCompile(`^[ \t]*\|?[ \t]*:?-+:?[ \t]*(?:\|[ \t]*:?-+:?[ \t]*)*\|?[ \t]*$`)
+)
+
+// IsMarkdownFile reports whether fileName is a Markdown document.
+func IsMarkdownFile(fileName string) bool {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".md", ".markdown":
+		return true
+	}
+	return false
+}
+
+// markdownParagraph is a paragraph or list item whose lines are joined into
+// one segment.
+type markdownParagraph struct {
+	prefix string
+	lines  []string
+	text   []string
+}
+
+// rewriteMarkdown translates Markdown block by block: headings, paragraphs,
+// list items, blockquotes and table cells are segments, with inline code,
+// URLs and link targets protected as placeholders. Front matter, fenced and
+// indented code, thematic breaks and link definitions are kept; HTML blocks
+// go through the markup rewriter. A translated paragraph is written on one
+// line, which renders the same as the soft-wrapped source.
+func rewriteMarkdown(data []byte, replace func(string) string) ([]byte, error) {
+	lines := strings.Split(string(data), "\n")
+	out := make([]string, 0, len(lines))
+	var paragraph *markdownParagraph
+	flush := func() {
+		if paragraph == nil {
+			return
+		}
+		source := strings.Join(paragraph.text, " ")
+		hardBreak := ""
+		if last := paragraph.lines[len(paragraph.lines)-1]; strings.HasSuffix(last, "  ") {
+			hardBreak = "  "
+		} else if strings.HasSuffix(source, `\`) {
+			source, hardBreak = strings.TrimSuffix(source, `\`), `\`
+		}
+		if translated := replaceTrimmed(source, replace); translated != source {
+			out = append(out, paragraph.prefix+translated+hardBreak)
+		} else {
+			out = append(out, paragraph.lines...)
+		}
+		paragraph = nil
+	}
+	i := 0
+	// Front matter is metadata, not text.
+	if len(lines) > 0 && (strings.TrimSpace(lines[0]) == "---" || strings.TrimSpace(lines[0]) == "+++") {
+		fence := strings.TrimSpace(lines[0])
+		for end := 1; end < len(lines); end++ {
+			if trimmed := strings.TrimSpace(lines[end]); trimmed == fence || (fence == "---" && trimmed == "...") {
+				out = append(out, lines[:end+1]...)
+				i = end + 1
+				break
+			}
+		}
+	}
+	fence := ""
+	inTable, inList, blank := false, false, true
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if fence != "" {
+			out = append(out, line)
+			if trimmed := strings.TrimSpace(markdownQuotePattern.ReplaceAllString(line, "")); strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				fence = ""
+			}
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			flush()
+			out = append(out, line)
+			inTable, blank = false, true
+			continue
+		}
+		quote := markdownQuotePattern.FindString(line)
+		rest := line[len(quote):]
+		if strings.Contains(quote, ">") {
+			// Inside a blockquote the indentation belongs to the content.
+			rest = strings.TrimLeft(rest, " \t")
+		} else {
+			quote, rest = "", line
+		}
+		wasBlank := blank
+		blank = false
+		switch {
+		case markdownFencePattern.MatchString(rest):
+			flush()
+			fence = markdownFencePattern.FindStringSubmatch(rest)[1]
+			out = append(out, line)
+		case wasBlank && paragraph == nil && !inList && quote == "" && strings.HasPrefix(strings.ReplaceAll(rest, "\t", "    "), "    "):
+			// Indented code block.
+			out = append(out, line)
+			blank = wasBlank
+		case paragraph != nil && markdownUnderlinePattern.MatchString(rest):
+			// Setext heading underline.
+			flush()
+			out = append(out, line)
+		case markdownBreakPattern.MatchString(rest), markdownDefinitionPattern.MatchString(rest):
+			flush()
+			out = append(out, line)
+		case quote == "" && paragraph == nil && markdownHTMLPattern.MatchString(rest):
+			end := i
+			for end < len(lines) && strings.TrimSpace(lines[end]) != "" {
+				end++
+			}
+			block, err := rewriteMarkup([]byte(strings.Join(lines[i:end], "\n")), replace)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, string(block))
+			i = end - 1
+		case markdownHeadingPattern.MatchString(rest):
+			flush()
+			m := markdownHeadingPattern.FindStringSubmatch(rest)
+			out = append(out, line[:len(line)-len(rest)]+m[1]+replaceTrimmed(m[2], replace)+m[3])
+			inList = false
+		case strings.Contains(rest, "|") && (inTable || i+1 < len(lines) && markdownDelimiterPattern.MatchString(lines[i+1][len(markdownQuotePattern.FindString(lines[i+1])):])):
+			flush()
+			inTable = true
+			if markdownDelimiterPattern.MatchString(rest) {
+				out = append(out, line)
+				continue
+			}
+			out = append(out, line[:len(line)-len(rest)]+rewriteMarkdownRow(rest, replace))
+		case markdownListPattern.MatchString(rest):
+			flush()
+			m := markdownListPattern.FindStringSubmatch(rest)
+			paragraph = &markdownParagraph{prefix: line[:len(line)-len(rest)] + m[1], lines: []string{line}, text: []string{strings.TrimSpace(m[2])}}
+			inList = true
+		default:
+			if paragraph == nil {
+				indent := rest[:len(rest)-len(strings.TrimLeft(rest, " \t"))]
+				paragraph = &markdownParagraph{prefix: line[:len(line)-len(rest)] + indent}
+				if wasBlank && indent == "" {
+					inList = false
+				}
+			}
+			paragraph.lines = append(paragraph.lines, line)
+			paragraph.text = append(paragraph.text, strings.TrimSpace(rest))
+		}
+		if paragraph != nil && (strings.HasSuffix(line, "  ") || strings.HasSuffix(line, `\`)) {
+			// A hard line break ends the segment.
+			flush()
+		}
+	}
+	flush()
+	return []byte(strings.Join(out, "\n")), nil
+}
+
+// rewriteMarkdownRow translates the cells of a table row. Pipes inside code
+// spans or escaped with a backslash do not split cells.
+func rewriteMarkdownRow(row string, replace func(string) string) string {
+	var builder strings.Builder
+	cell := 0
+	inCode := false
+	for i := 0; i < len(row); i++ {
+		switch {
+		case row[i] == '\\' && i+1 < len(row):
+			i++
+		case row[i] == '`':
+			inCode = !inCode
+		case row[i] == '|' && !inCode:
+			builder.WriteString(replaceTrimmed(row[cell:i], replace))
+			builder.WriteByte('|')
+			cell = i + 1
+		}
+	}
+	builder.WriteString(replaceTrimmed(row[cell:], replace))
+	return builder.String()
+}
@@ -0,0 +1,90 @@
+package translation
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+const sampleMarkdown = `---
+title: Guide
+---
+# Getting started #
+
+Install the tool
+with ` + "`go install`" + `.
+
+- First [step](https://example.com/a)
+- [ ] Second step
+
+> Quoted text
+> continues here.
+
+| Name | Value |
+| ---- | ----: |
+| Size | ` + "`a|b`" + ` |
+
+` + "```go" + `
+fmt.Println("code")
+` + "```" + `
+
+    indented code
+
+![Logo](logo.png)
+[docs]: https://example.com/docs
+`
+
+func TestMarkdownSegments(t *testing.T) {
+	segments, err := DocumentSegments("guide.md", []byte(sampleMarkdown))
+	if err != nil {
+		t.Fatalf("segments: %v", err)
+	}
+	expected := []string{
+		"Getting started",
+		"Install the tool with `go install`.",
+		"First [step](https://example.com/a)",
+		"Second step",
+		"Quoted text continues here.",
+		"Name", "Value",
+		"Size", "`a|b`",
+		"![Logo](logo.png)",
+	}
+	if !reflect.DeepEqual(segments, expected) {
+		t.Fatalf("expected %q got %q", expected, segments)
+	}
+}
+
+func TestReassembleMarkdownKeepsStructure(t *testing.T) {
+	out, err := ReassembleDocument(context.Background(), "guide.md", "DE", []byte(sampleMarkdown), upperSegments)
+	if err != nil {
+		t.Fatalf("reassemble: %v", err)
+	}
+	expected := `---
+title: Guide
+---
+# GETTING STARTED #
+
+INSTALL THE TOOL WITH ` + "`GO INSTALL`" + `.
+
+- FIRST [STEP](HTTPS://EXAMPLE.COM/A)
+- [ ] SECOND STEP
+
+> QUOTED TEXT CONTINUES HERE.
+
+| NAME | VALUE |
+| ---- | ----: |
+| SIZE | ` + "`A|B`" + ` |
+
+` + "```go" + `
+fmt.Println("code")
+` + "```" + `
+
+    indented code
+
+![LOGO](LOGO.PNG)
+[docs]: https://example.com/docs
+`
+	if string(out) != expected {
+		t.Fatalf("expected %q got %q", expected, out)
+	}
+}
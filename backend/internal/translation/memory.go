@@ -0,0 +1,131 @@
+package translation
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+	"strings"
+)
+
+// SegmentTranslator is implemented by providers that translate individual
+// segments in batches. Providers without it translate segment by segment
+// through TranslateText.
+type SegmentTranslator interface {
+	TranslateSegments(ctx context.Context, segments []string, req TextRequest) ([]string, error)
+}
+
+// NormalizeSegment collapses whitespace so that reflowed text still matches
+// the translation memory.
+func NormalizeSegment(segment string) string {
+	return strings.Join(strings.Fields(segment), " ")
+}
+
+// SegmentHash keys a segment in the translation memory.
+func SegmentHash(segment string) string {
+	sum := sha256.Sum256([]byte(NormalizeSegment(segment)))
+	return hex.EncodeToString(sum[:])
+}
+
+// DocumentSegments lists the segments a reassemblable document is
+// translated in, in document order.
+func DocumentSegments(fileName string, data []byte) ([]string, error) {
+	var segments []string
+	_, err := ReassembleDocument(context.Background(), fileName, "", data, func(_ context.Context, s []string) ([]string, error) {
+		segments = s
+		return s, nil
+	})
+	return segments, err
+}
+
+// TranslatedSegments lists the segments of a document translated into
+// targetLang, aligned with the DocumentSegments of its source. Bilingual
+// formats keep the source, so their targets are read from the target side.
+func TranslatedSegments(fileName, targetLang string, data []byte) ([]string, error) {
+	if read, ok := targetReaders[strings.ToLower(filepath.Ext(fileName))]; ok {
+		return read(data, targetLang)
+	}
+	return DocumentSegments(fileName, data)
+}
+
+// translateWithMemory reassembles the document from stored translations and
+// sends only the remaining segments to the provider.
+func translateWithMemory(ctx context.Context, provider Provider, data []byte, req DocumentRequest) ([]byte, error) {
+	return ReassembleDocument(ctx, req.FileName, req.TargetLang, data, func(ctx context.Context, segments []string) ([]string, error) {
+		return translateSegmentsWithMemory(ctx, provider, segments, req)
+	})
+}
+
+// translateSegmentsWithMemory takes the targets of stored segments from
+// req.Memory and translates the rest. A stored target whose placeholders
+// differ from the segment's, such as one imported with other inline codes,
+// is not reused.
+func translateSegmentsWithMemory(ctx context.Context, provider Provider, segments []string, req DocumentRequest) ([]string, error) {
+	targets := make([]string, len(segments))
+	var missing []string
+	var missingIdx []int
+	for i, segment := range segments {
+		if target, ok := req.Memory[NormalizeSegment(segment)]; ok {
+			if _, ok := comparePlaceholders(segment, target); ok {
+				targets[i] = target
+				continue
+			}
+		}
+		missing = append(missing, segment)
+		missingIdx = append(missingIdx, i)
+	}
+	if len(missing) == 0 {
+		return targets, nil
+	}
+	translated, err := translateSegmentsWith(ctx, provider, missing, TextRequest{
+		SourceLang: req.SourceLang,
+		TargetLang: req.TargetLang,
+		Engine:     req.Engine,
+		Formality:  req.Formality,
+		GlossaryID: req.GlossaryID,
+		Passes:     req.Passes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, idx := range missingIdx {
+		targets[idx] = translated[i]
+	}
+	return targets, nil
+}
+
+func translateSegmentsWith(ctx context.Context, provider Provider, segments []string, req TextRequest) ([]string, error) {
+	if st, ok := provider.(SegmentTranslator); ok {
+		return st.TranslateSegments(ctx, segments, req)
+	}
+	masked, masks := MaskSegments(segments)
+	targets := make([]string, len(masked))
+	for i, segment := range masked {
+		target, err := provider.TranslateText(ctx, segment, req)
+		if err != nil {
+			return nil, err
+		}
+		targets[i] = target
+	}
+	return RestoreSegments(masks, targets)
+}
+
+// usesMemory reports whether req can be served partly from memory. Formats
+// reassembled with loss of formatting are translated as documents even when
+// memory matches.
+func (req DocumentRequest) usesMemory() bool {
+	return len(req.Memory) > 0 && ReassemblesLosslessly(req.FileName)
+}
+
+// usesSegments reports whether req is translated segment by segment rather
+// than sent to the provider as a document: with memory, and always for
+// formats document providers do not read.
+func (req DocumentRequest) usesSegments() bool {
+	return req.usesMemory() || translatesBySegment(req.FileName)
+}
+
+// translatesBySegment reports whether documents of fileName's format are
+// always translated segment by segment.
+func translatesBySegment(fileName string) bool {
+	return IsMarkdownFile(fileName) || IsLocalizationFile(fileName) || IsSubtitleFile(fileName)
+}
@@ -0,0 +1,78 @@
+package translation
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type recordingSegmentProvider struct {
+	stubProvider
+	sent []string
+}
+
+func (p *recordingSegmentProvider) TranslateSegments(_ context.Context, segments []string, _ TextRequest) ([]string, error) {
+	p.sent = append(p.sent, segments...)
+	targets := make([]string, len(segments))
+	for i, segment := range segments {
+		targets[i] = strings.ToUpper(segment)
+	}
+	return targets, nil
+}
+
+func TestTranslateWithMemorySkipsStoredSegments(t *testing.T) {
+	provider := &recordingSegmentProvider{stubProvider: stubProvider{providerType: ProviderDeepL}}
+	req := DocumentRequest{
+		FileName: "contract.txt",
+		Memory:   map[string]string{"Second  clause.": "unused", "Second clause.": "Zweite Klausel."},
+	}
+	out, err := translateWithMemory(context.Background(), provider, []byte("First clause.\n  Second   clause.\nThird clause.\n"), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "FIRST CLAUSE.\n  Zweite Klausel.\nTHIRD CLAUSE.\n"; string(out) != want {
+		t.Fatalf("expected %q got %q", want, out)
+	}
+	if len(provider.sent) != 2 {
+		t.Fatalf("expected only unmatched segments to be sent, got %q", provider.sent)
+	}
+}
+
+func TestTranslateWithMemoryIgnoresHitsWithOtherPlaceholders(t *testing.T) {
+	provider := &recordingSegmentProvider{stubProvider: stubProvider{providerType: ProviderDeepL}}
+	req := DocumentRequest{
+		FileName: "notes.txt",
+		Memory:   map[string]string{"Hello %s": "Hallo", "Bye": "Tschüss"},
+	}
+	out, err := translateWithMemory(context.Background(), provider, []byte("Hello %s\nBye\n"), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "HELLO %S\nTschüss\n"; string(out) != want {
+		t.Fatalf("expected %q got %q", want, out)
+	}
+	if len(provider.sent) != 1 || provider.sent[0] != "Hello %s" {
+		t.Fatalf("expected the mismatched hit to be translated, got %q", provider.sent)
+	}
+}
+
+func TestMemoryOnlyRoutesLosslessFormatsToSegments(t *testing.T) {
+	memory := map[string]string{"Hello": "Hallo"}
+	if !(DocumentRequest{FileName: "notes.html", Memory: memory}).usesSegments() {
+		t.Fatal("expected HTML with memory hits to be translated by segment")
+	}
+	for _, name := range []string{"report.docx", "book.epub"} {
+		if (DocumentRequest{FileName: name, Memory: memory}).usesSegments() {
+			t.Fatalf("expected %s to keep the document path", name)
+		}
+	}
+}
+
+func TestSegmentHashNormalizesWhitespace(t *testing.T) {
+	if SegmentHash("Hello   world\n") != SegmentHash(" Hello world") {
+		t.Fatal("expected whitespace-insensitive hashes")
+	}
+	if SegmentHash("Hello world") == SegmentHash("hello world") {
+		t.Fatal("expected case-sensitive hashes")
+	}
+}
@@ -0,0 +1,236 @@
+package translation
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/models"
+)
+
+// ProviderMock is the offline pseudo-localization provider.
+const ProviderMock ProviderType = "mock"
+
+// ErrMockFailure is returned when the mock provider injects a failure.
+var ErrMockFailure = errors.New("mock: injected failure")
+
+// MockModel is the catalog entry served by the mock provider.
+var MockModel = Model{
+	ModelDescriptor: models.ModelDescriptor{
+		Key:          "kaminskyi-mock",
+		DisplayName:  "Kaminskyi Mock",
+		Provider:     string(ProviderMock),
+		Tier:         "Mock",
+		PricePer1860: 0.01,
+		Currency:     "EUR",
+		Features: []string{
+			"Pseudo-Lokalisierung ohne externe API",
+			"Konfigurierbare Latenz und Fehler",
+		},
+		Options: map[string]string{
+			"priority": "standard",
+		},
+		MaxCharacters: 500000,
+		SpeedScore:    10,
+		AccuracyScore: 1,
+	},
+	Provider:          ProviderMock,
+	Engine:            "mock-pseudo",
+	SupportsFormality: true,
+	SupportsGlossary:  true,
+	SupportsDocuments: true,
+	SupportsImages:    false,
+	SupportsXMLTags:   true,
+}
+
+// MockOptions configure the mock provider.
+type MockOptions struct {
+	Latency     time.Duration
+	FailureRate float64
+	Seed        int64
+}
+
+// MockProvider translates deterministically without network access by
+// pseudo-localizing text: letters are accented and segments bracketed.
+type MockProvider struct {
+	latency     time.Duration
+	failureRate float64
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// NewMockProvider constructs the mock provider.
+func NewMockProvider(opts MockOptions) *MockProvider {
+	return &MockProvider{
+		latency:     opts.Latency,
+		failureRate: opts.FailureRate,
+		rand:        rand.New(rand.NewSource(opts.Seed)),
+	}
+}
+
+// Type implements Provider.
+func (p *MockProvider) Type() ProviderType {
+	return ProviderMock
+}
+
+// Capabilities implements Provider.
+func (p *MockProvider) Capabilities() Capabilities {
+	return Capabilities{
+		Documents: true,
+		Text:      true,
+		Formality: true,
+		Glossary:  true,
+		XMLTags:   true,
+	}
+}
+
+// TranslateDocument pseudo-localizes plain text, markup and the XML parts of
+// DOCX/EPUB archives. Other binary formats are returned unchanged.
+func (p *MockProvider) TranslateDocument(ctx context.Context, reader io.Reader, req DocumentRequest) ([]byte, error) {
+	if err := p.simulate(ctx); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(filepath.Ext(req.FileName)) {
+	case ".docx", ".epub":
+		return pseudoLocalizeArchive(data)
+	case ".pdf":
+		return data, nil
+	default:
+		if !utf8.Valid(data) {
+			return data, nil
+		}
+		return []byte(PseudoLocalizeMarkup(string(data))), nil
+	}
+}
+
+// TranslateText implements Provider.
+func (p *MockProvider) TranslateText(ctx context.Context, text string, req TextRequest) (string, error) {
+	if err := p.simulate(ctx); err != nil {
+		return "", err
+	}
+	return PseudoLocalizeMarkup(text), nil
+}
+
+// Languages implements LanguageSource with the static list.
+func (p *MockProvider) Languages(ctx context.Context) (*LanguageSet, error) {
+	set := StaticLanguages
+	return &set, nil
+}
+
+// Health implements Provider.
+func (p *MockProvider) Health(ctx context.Context) error {
+	if p.failureRate >= 1 {
+		return ErrMockFailure
+	}
+	return nil
+}
+
+func (p *MockProvider) simulate(ctx context.Context) error {
+	if p.latency > 0 {
+		timer := time.NewTimer(p.latency)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	if p.failureRate <= 0 {
+		return nil
+	}
+	p.mu.Lock()
+	roll := p.rand.Float64()
+	p.mu.Unlock()
+	if roll < p.failureRate {
+		return ErrMockFailure
+	}
+	return nil
+}
+
+var pseudoAccents = map[rune]rune{
+	'a': 'á', 'b': 'ƀ', 'c': 'ç', 'd': 'ð', 'e': 'é', 'f': 'ƒ', 'g': 'ĝ', 'h': 'ĥ', 'i': 'í',
+	'j': 'ĵ', 'k': 'ķ', 'l': 'ļ', 'm': 'ɱ', 'n': 'ñ', 'o': 'ö', 'p': 'þ', 'q': 'ǫ', 'r': 'ŕ',
+	's': 'š', 't': 'ţ', 'u': 'ü', 'v': 'ṽ', 'w': 'ŵ', 'x': 'ẋ', 'y': 'ý', 'z': 'ž',
+	'A': 'Á', 'B': 'Ɓ', 'C': 'Ç', 'D': 'Ð', 'E': 'É', 'F': 'Ƒ', 'G': 'Ĝ', 'H': 'Ĥ', 'I': 'Í',
+	'J': 'Ĵ', 'K': 'Ķ', 'L': 'Ļ', 'M': 'Ṁ', 'N': 'Ñ', 'O': 'Ö', 'P': 'Þ', 'Q': 'Ǫ', 'R': 'Ŕ',
+	'S': 'Š', 'T': 'Ţ', 'U': 'Ü', 'V': 'Ṽ', 'W': 'Ŵ', 'X': 'Ẋ', 'Y': 'Ý', 'Z': 'Ž',
+}
+
+// PseudoLocalize accents every ASCII letter and brackets each non-blank line.
+func PseudoLocalize(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		start := strings.Index(line, trimmed)
+		lines[i] = line[:start] + "[" + accent(trimmed) + "]" + line[start+len(trimmed):]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// PseudoLocalizeMarkup pseudo-localizes text nodes and leaves tags intact.
+func PseudoLocalizeMarkup(text string) string {
+	if !strings.Contains(text, "<") {
+		return PseudoLocalize(text)
+	}
+	var builder strings.Builder
+	for len(text) > 0 {
+		open := strings.IndexByte(text, '<')
+		if open < 0 {
+			builder.WriteString(PseudoLocalize(text))
+			break
+		}
+		builder.WriteString(PseudoLocalize(text[:open]))
+		closing := strings.IndexByte(text[open:], '>')
+		if closing < 0 {
+			builder.WriteString(text[open:])
+			break
+		}
+		builder.WriteString(text[open : open+closing+1])
+		text = text[open+closing+1:]
+	}
+	return builder.String()
+}
+
+// accent maps letters to accented variants, leaving character entities such
+// as &amp; untouched so markup stays well-formed.
+func accent(text string) string {
+	var builder strings.Builder
+	inEntity := false
+	for _, r := range text {
+		switch {
+		case r == '&':
+			inEntity = true
+		case inEntity && r == ';':
+			inEntity = false
+		case inEntity && r == ' ':
+			inEntity = false
+		}
+		if mapped, ok := pseudoAccents[r]; ok && !inEntity {
+			r = mapped
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+func pseudoLocalizeArchive(data []byte) ([]byte, error) {
+	return rewriteArchive(data, func(name string) bool {
+		return strings.ToLower(name) == "word/document.xml" || isMarkupPart(name)
+	}, func(content []byte) []byte {
+		return []byte(PseudoLocalizeMarkup(string(content)))
+	})
+}
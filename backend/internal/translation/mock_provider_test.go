@@ -0,0 +1,62 @@
+package translation
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestPseudoLocalizeMarkup(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"plain", "Hello world", "[Ĥéļļö ŵöŕļð]"},
+		{"lines", "One\n\n  Two", "[Öñé]\n\n  [Ţŵö]"},
+		{"markup", "<p class=\"x\">Hi &amp; bye</p>", "<p class=\"x\">[Ĥí &amp; ƀýé]</p>"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := PseudoLocalizeMarkup(tc.input)
+			if got != tc.expected {
+				t.Fatalf("expected %q got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestMockProviderDocx(t *testing.T) {
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	w, _ := zw.Create("word/document.xml")
+	w.Write([]byte("<w:t>Hello</w:t>"))
+	zw.Close()
+
+	provider := NewMockProvider(MockOptions{})
+	out, err := provider.TranslateDocument(context.Background(), bytes.NewReader(buf.Bytes()), DocumentRequest{FileName: "a.docx"})
+	if err != nil {
+		t.Fatalf("translate: %v", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(out), int64(len(out)))
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	rc, _ := zr.File[0].Open()
+	content, _ := io.ReadAll(rc)
+	if !strings.Contains(string(content), "[Ĥéļļö]") {
+		t.Fatalf("unexpected document content %q", content)
+	}
+}
+
+func TestMockProviderFailureInjection(t *testing.T) {
+	provider := NewMockProvider(MockOptions{FailureRate: 1})
+	if _, err := provider.TranslateText(context.Background(), "hi", TextRequest{}); !errors.Is(err, ErrMockFailure) {
+		t.Fatalf("expected injected failure, got %v", err)
+	}
+}
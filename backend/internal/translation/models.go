@@ -1,6 +1,10 @@
 package translation
 
-import "github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/models"
+import (
+	"sync"
+
+	"github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/models"
+)
 
 // ProviderType enumerates translation providers.
 type ProviderType string
@@ -20,9 +24,14 @@ type Model struct {
 	SupportsDocuments bool
 	SupportsImages    bool
 	SupportsXMLTags   bool
+	Fallbacks         []Route
+	// Disabled models are not offered for new translations; queued ones
+	// still finish.
+	Disabled bool
 }
 
-// Catalog lists supported models.
+// Catalog lists the built-in models. They seed the model catalog, which
+// is served through GetModelByKey and ListModelDescriptors.
 var Catalog = []Model{
 	{
 		ModelDescriptor: models.ModelDescriptor{
@@ -52,6 +61,9 @@ var Catalog = []Model{
 		SupportsDocuments: true,
 		SupportsImages:    false,
 		SupportsXMLTags:   true,
+		Fallbacks: []Route{
+			{Provider: ProviderOTranslator, Engine: "otranslator-elite"},
+		},
 	},
 	{
 		ModelDescriptor: models.ModelDescriptor{
@@ -81,6 +93,9 @@ var Catalog = []Model{
 		SupportsDocuments: true,
 		SupportsImages:    false,
 		SupportsXMLTags:   true,
+		Fallbacks: []Route{
+			{Provider: ProviderOTranslator, Engine: "otranslator-elite"},
+		},
 	},
 	{
 		ModelDescriptor: models.ModelDescriptor{
@@ -110,6 +125,9 @@ var Catalog = []Model{
 		SupportsDocuments: true,
 		SupportsImages:    false,
 		SupportsXMLTags:   true,
+		Fallbacks: []Route{
+			{Provider: ProviderOTranslator, Engine: "otranslator-elite"},
+		},
 	},
 	{
 		ModelDescriptor: models.ModelDescriptor{
@@ -139,6 +157,9 @@ var Catalog = []Model{
 		SupportsDocuments: true,
 		SupportsImages:    true,
 		SupportsXMLTags:   true,
+		Fallbacks: []Route{
+			{Provider: ProviderDeepL, Engine: "deepl-pro"},
+		},
 	},
 	{
 		ModelDescriptor: models.ModelDescriptor{
@@ -168,6 +189,9 @@ var Catalog = []Model{
 		SupportsDocuments: true,
 		SupportsImages:    true,
 		SupportsXMLTags:   true,
+		Fallbacks: []Route{
+			{Provider: ProviderDeepL, Engine: "deepl-pro"},
+		},
 	},
 	{
 		ModelDescriptor: models.ModelDescriptor{
@@ -197,12 +221,40 @@ var Catalog = []Model{
 		SupportsDocuments: true,
 		SupportsImages:    true,
 		SupportsXMLTags:   true,
+		Fallbacks: []Route{
+			{Provider: ProviderDeepL, Engine: "deepl-pro"},
+		},
 	},
 }
 
-// GetModelByKey returns model descriptor by key.
+var (
+	catalogMu sync.RWMutex
+	catalog   []Model
+)
+
+// SetCatalog replaces the models served by GetModelByKey, Models and
+// ListModelDescriptors.
+func SetCatalog(models []Model) {
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+	catalog = append([]Model(nil), models...)
+}
+
+// Models returns the current catalog including disabled models. Until
+// SetCatalog is called it is the built-in Catalog.
+func Models() []Model {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+	if catalog == nil {
+		return append([]Model(nil), Catalog...)
+	}
+	return append([]Model(nil), catalog...)
+}
+
+// GetModelByKey returns model descriptor by key. Disabled models are
+// returned too so that queued translations complete.
 func GetModelByKey(key string) *Model {
-	for _, model := range Catalog {
+	for _, model := range Models() {
 		if model.ModelDescriptor.Key == key {
 			m := model
 			return &m
@@ -211,11 +263,83 @@ func GetModelByKey(key string) *Model {
 	return nil
 }
 
-// ListModelDescriptors returns sanitized descriptors for API responses.
+// ListModelDescriptors returns sanitized descriptors of the enabled models
+// with their option schema for API responses.
 func ListModelDescriptors() []models.ModelDescriptor {
-	descriptors := make([]models.ModelDescriptor, 0, len(Catalog))
-	for _, model := range Catalog {
-		descriptors = append(descriptors, model.ModelDescriptor)
+	current := Models()
+	descriptors := make([]models.ModelDescriptor, 0, len(current))
+	for _, model := range current {
+		if !model.Disabled {
+			descriptor := model.ModelDescriptor
+			descriptor.OptionSchema = model.OptionSchema()
+			descriptors = append(descriptors, descriptor)
+		}
 	}
 	return descriptors
 }
+
+// ModelFromCatalog converts a stored catalog entry.
+func ModelFromCatalog(entry models.CatalogModel) Model {
+	model := Model{
+		ModelDescriptor: models.ModelDescriptor{
+			Key:           entry.Key,
+			DisplayName:   entry.DisplayName,
+			Provider:      entry.Provider,
+			Tier:          entry.Tier,
+			PricePer1860:  entry.PricePer1860,
+			Currency:      entry.Currency,
+			Features:      append([]string{}, entry.Features...),
+			Options:       map[string]string{},
+			MaxCharacters: entry.MaxCharacters,
+			SpeedScore:    entry.SpeedScore,
+			AccuracyScore: entry.AccuracyScore,
+		},
+		Provider:          ProviderType(entry.Provider),
+		Engine:            entry.Engine,
+		SupportsFormality: entry.SupportsFormality,
+		SupportsGlossary:  entry.SupportsGlossary,
+		SupportsDocuments: entry.SupportsDocuments,
+		SupportsImages:    entry.SupportsImages,
+		SupportsXMLTags:   entry.SupportsXMLTags,
+		Disabled:          !entry.Enabled,
+	}
+	for key, value := range entry.Options {
+		model.Options[key] = value
+	}
+	for _, route := range entry.Fallbacks {
+		model.Fallbacks = append(model.Fallbacks, Route{Provider: ProviderType(route.Provider), Engine: route.Engine})
+	}
+	return model
+}
+
+// CatalogEntry converts the model for storage in the catalog.
+func (m Model) CatalogEntry() models.CatalogModel {
+	entry := models.CatalogModel{
+		Key:               m.Key,
+		DisplayName:       m.DisplayName,
+		Tier:              m.Tier,
+		Provider:          string(m.Provider),
+		Engine:            m.Engine,
+		PricePer1860:      m.PricePer1860,
+		Currency:          m.Currency,
+		Features:          append(models.StringList{}, m.Features...),
+		Options:           models.StringMap{},
+		MaxCharacters:     m.MaxCharacters,
+		SpeedScore:        m.SpeedScore,
+		AccuracyScore:     m.AccuracyScore,
+		SupportsFormality: m.SupportsFormality,
+		SupportsGlossary:  m.SupportsGlossary,
+		SupportsDocuments: m.SupportsDocuments,
+		SupportsImages:    m.SupportsImages,
+		SupportsXMLTags:   m.SupportsXMLTags,
+		Fallbacks:         models.ModelRoutes{},
+		Enabled:           !m.Disabled,
+	}
+	for key, value := range m.Options {
+		entry.Options[key] = value
+	}
+	for _, route := range m.Fallbacks {
+		entry.Fallbacks = append(entry.Fallbacks, models.ModelRoute{Provider: string(route.Provider), Engine: route.Engine})
+	}
+	return entry
+}
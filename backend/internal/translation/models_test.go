@@ -0,0 +1,29 @@
+package translation
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCatalogEntryRoundTrip(t *testing.T) {
+	for _, model := range Catalog {
+		if got := ModelFromCatalog(model.CatalogEntry()); !reflect.DeepEqual(got, model) {
+			t.Errorf("%s: expected %+v, got %+v", model.Key, model, got)
+		}
+	}
+}
+
+func TestSetCatalogHidesDisabledModels(t *testing.T) {
+	defer SetCatalog(Catalog)
+	disabled := Catalog[1]
+	disabled.Disabled = true
+	SetCatalog([]Model{Catalog[0], disabled})
+
+	descriptors := ListModelDescriptors()
+	if len(descriptors) != 1 || descriptors[0].Key != Catalog[0].Key {
+		t.Fatalf("expected only %s to be listed, got %+v", Catalog[0].Key, descriptors)
+	}
+	if model := GetModelByKey(disabled.Key); model == nil || !model.Disabled {
+		t.Fatalf("expected disabled model %s to stay resolvable", disabled.Key)
+	}
+}
@@ -0,0 +1,209 @@
+package translation
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/olehkaminskyi/kaminskyi-language-intelligence/internal/models"
+)
+
+// maxPasses bounds the translation passes a multi-pass model may run.
+const maxPasses = 3
+
+// formalityValues are the formality levels understood by every provider.
+var formalityValues = []string{"default", "more", "less", "prefer_more", "prefer_less"}
+
+// knownOptions lists every option key, so keys a model does not offer are
+// reported as unsupported rather than unknown.
+var knownOptions = map[string]bool{
+	"formality":       true,
+	"tag_handling":    true,
+	"passes":          true,
+	"ignore_comments": true,
+	"glossary":        true,
+	"split_oversized": true,
+}
+
+// OptionError reports invalid translation options by option key.
+type OptionError struct {
+	Fields map[string]string
+}
+
+func (e *OptionError) Error() string {
+	keys := make([]string, 0, len(e.Fields))
+	for key := range e.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = key + ": " + e.Fields[key]
+	}
+	return "invalid options: " + strings.Join(parts, "; ")
+}
+
+// OptionSchema returns the options the model accepts, derived from its
+// capabilities. Defaults come from the descriptor's Options.
+func (m Model) OptionSchema() []models.OptionSpec {
+	var schema []models.OptionSpec
+	if m.SupportsFormality {
+		schema = append(schema, models.OptionSpec{
+			Key:         "formality",
+			Type:        models.OptionEnum,
+			Description: "Register of the translation",
+			Values:      formalityValues,
+			Default:     m.Options["formality"],
+		})
+	}
+	if m.SupportsXMLTags {
+		schema = append(schema, models.OptionSpec{
+			Key:         "tag_handling",
+			Type:        models.OptionEnum,
+			Description: "Treat the text as XML or HTML and keep its tags",
+			Values:      []string{"xml", "html"},
+			Default:     m.Options["tag_handling"],
+		})
+	}
+	if m.Options["passes"] != "" {
+		minimum, maximum := 1, maxPasses
+		spec := models.OptionSpec{
+			Key:         "passes",
+			Type:        models.OptionInteger,
+			Description: "Number of translation and review passes",
+			Min:         &minimum,
+			Max:         &maximum,
+		}
+		if passes, err := strconv.Atoi(m.Options["passes"]); err == nil {
+			spec.Default = passes
+		}
+		schema = append(schema, spec)
+	}
+	if m.SupportsDocuments {
+		schema = append(schema, models.OptionSpec{
+			Key:         "ignore_comments",
+			Type:        models.OptionBoolean,
+			Description: "Leave document comments untranslated",
+		})
+	}
+	if m.SupportsGlossary {
+		schema = append(schema, models.OptionSpec{
+			Key:         "glossary",
+			Type:        models.OptionString,
+			Description: "ID of one of your glossaries",
+		})
+	}
+	schema = append(schema, models.OptionSpec{
+		Key:         "split_oversized",
+		Type:        models.OptionBoolean,
+		Description: "Translate documents above the character limit in parts",
+	})
+	for i := range schema {
+		if schema[i].Default == "" {
+			schema[i].Default = nil
+		}
+	}
+	return schema
+}
+
+// ValidateOptions checks values against the model's option schema and
+// returns them typed, with the schema defaults filled in. Invalid values
+// and options the model does not offer are reported in an *OptionError.
+func (m Model) ValidateOptions(values map[string]interface{}) (map[string]interface{}, error) {
+	schema := m.OptionSchema()
+	specs := make(map[string]models.OptionSpec, len(schema))
+	for _, spec := range schema {
+		specs[spec.Key] = spec
+	}
+	fields := make(map[string]string)
+	options := make(map[string]interface{})
+	for key, value := range values {
+		spec, ok := specs[key]
+		switch {
+		case !ok && knownOptions[key]:
+			fields[key] = fmt.Sprintf("not supported by %s", m.DisplayName)
+		case !ok:
+			fields[key] = "unknown option"
+		case value == nil:
+			// An explicit null selects the default.
+		default:
+			typed, err := checkOption(spec, value)
+			if err != nil {
+				fields[key] = err.Error()
+				continue
+			}
+			options[key] = typed
+		}
+	}
+	if len(fields) > 0 {
+		return nil, &OptionError{Fields: fields}
+	}
+	for _, spec := range schema {
+		if _, ok := options[spec.Key]; !ok && spec.Default != nil {
+			options[spec.Key] = spec.Default
+		}
+	}
+	return options, nil
+}
+
+// OptionsForTarget adapts validated options to one target language. Only
+// targets that support formality receive it: the model default is dropped
+// for the others, and a formality the user requested for them is reported
+// in an *OptionError, as providers reject it.
+func OptionsForTarget(options, requested map[string]interface{}, target Language) (map[string]interface{}, error) {
+	if _, ok := options["formality"]; !ok || target.SupportsFormality {
+		return options, nil
+	}
+	if requested["formality"] != nil {
+		return nil, &OptionError{Fields: map[string]string{
+			"formality": fmt.Sprintf("not supported for target language %s", target.Code),
+		}}
+	}
+	adapted := make(map[string]interface{}, len(options))
+	for key, value := range options {
+		if key != "formality" {
+			adapted[key] = value
+		}
+	}
+	return adapted, nil
+}
+
+func checkOption(spec models.OptionSpec, value interface{}) (interface{}, error) {
+	switch spec.Type {
+	case models.OptionEnum:
+		text, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("must be a string")
+		}
+		for _, allowed := range spec.Values {
+			if text == allowed {
+				return text, nil
+			}
+		}
+		return nil, fmt.Errorf("must be one of %s", strings.Join(spec.Values, ", "))
+	case models.OptionInteger:
+		number, ok := value.(float64)
+		if !ok || number != math.Trunc(number) {
+			return nil, fmt.Errorf("must be an integer")
+		}
+		n := int(number)
+		if (spec.Min != nil && n < *spec.Min) || (spec.Max != nil && n > *spec.Max) {
+			return nil, fmt.Errorf("must be between %d and %d", *spec.Min, *spec.Max)
+		}
+		return n, nil
+	case models.OptionBoolean:
+		flag, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("must be true or false")
+		}
+		return flag, nil
+	default:
+		text, ok := value.(string)
+		if !ok || strings.TrimSpace(text) == "" {
+			return nil, fmt.Errorf("must be a non-empty string")
+		}
+		return strings.TrimSpace(text), nil
+	}
+}
@@ -0,0 +1,102 @@
+package translation
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestOptionSchemaFollowsCapabilities(t *testing.T) {
+	model := Catalog[0]
+	model.SupportsGlossary = false
+	model.SupportsXMLTags = false
+	keys := map[string]bool{}
+	for _, spec := range model.OptionSchema() {
+		keys[spec.Key] = true
+		if spec.Key == "formality" && spec.Default != "default" {
+			t.Fatalf("expected formality default from descriptor options, got %v", spec.Default)
+		}
+	}
+	if !keys["formality"] || keys["glossary"] || keys["tag_handling"] || keys["passes"] {
+		t.Fatalf("unexpected schema keys %v", keys)
+	}
+}
+
+func TestValidateOptionsAppliesDefaults(t *testing.T) {
+	model := Catalog[len(Catalog)-1]
+	options, err := model.ValidateOptions(map[string]interface{}{"formality": "less", "ignore_comments": true})
+	if err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if options["formality"] != "less" || options["ignore_comments"] != true {
+		t.Fatalf("unexpected options %v", options)
+	}
+	if options["passes"] != 3 {
+		t.Fatalf("expected passes default 3, got %v", options["passes"])
+	}
+}
+
+func TestValidateOptionsReportsFields(t *testing.T) {
+	model := Catalog[0]
+	model.SupportsGlossary = false
+	_, err := model.ValidateOptions(map[string]interface{}{
+		"formality": "rude",
+		"passes":    2.0,
+		"glossary":  "g1",
+		"colour":    "red",
+	})
+	var optionErr *OptionError
+	if !errors.As(err, &optionErr) {
+		t.Fatalf("expected option error, got %v", err)
+	}
+	for _, key := range []string{"formality", "passes", "glossary", "colour"} {
+		if optionErr.Fields[key] == "" {
+			t.Fatalf("expected an error for %s, got %v", key, optionErr.Fields)
+		}
+	}
+}
+
+func TestValidateOptionsChecksTypes(t *testing.T) {
+	model := Catalog[len(Catalog)-1]
+	_, err := model.ValidateOptions(map[string]interface{}{"passes": 2.5, "ignore_comments": "yes"})
+	var optionErr *OptionError
+	if !errors.As(err, &optionErr) || len(optionErr.Fields) != 2 {
+		t.Fatalf("expected two field errors, got %v", err)
+	}
+	if _, err := model.ValidateOptions(map[string]interface{}{"passes": 4.0}); err == nil {
+		t.Fatal("expected passes above the maximum to be rejected")
+	}
+}
+
+func TestOptionsForTargetDropsUnsupportedFormality(t *testing.T) {
+	model := Catalog[0]
+	model.Options = map[string]string{"formality": "more"}
+	options, err := model.ValidateOptions(nil)
+	if err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	enUS, ok := StaticLanguages.TargetLanguage("EN-US")
+	if !ok {
+		t.Fatal("EN-US missing from the static languages")
+	}
+	adapted, err := OptionsForTarget(options, nil, enUS)
+	if err != nil {
+		t.Fatalf("default formality for EN-US: %v", err)
+	}
+	if _, ok := adapted["formality"]; ok {
+		t.Fatalf("formality default sent to EN-US: %v", adapted)
+	}
+	if options["formality"] != "more" {
+		t.Fatalf("validated options were modified: %v", options)
+	}
+
+	requested := map[string]interface{}{"formality": "less"}
+	options, _ = model.ValidateOptions(requested)
+	var optionErr *OptionError
+	if _, err := OptionsForTarget(options, requested, enUS); !errors.As(err, &optionErr) || optionErr.Fields["formality"] == "" {
+		t.Fatalf("expected a formality field error for EN-US, got %v", err)
+	}
+	de, _ := StaticLanguages.TargetLanguage("DE")
+	if adapted, err := OptionsForTarget(options, requested, de); err != nil || adapted["formality"] != "less" {
+		t.Fatalf("expected formality kept for DE, got %v, %v", adapted, err)
+	}
+}
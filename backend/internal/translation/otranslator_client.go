@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
@@ -16,12 +17,14 @@ type OTranslatorClient struct {
 	httpClient *http.Client
 	apiKey     string
 	baseURL    string
+	retry      RetryPolicy
 }
 
 // OTranslatorOptions configures the client.
 type OTranslatorOptions struct {
 	BaseURL string
 	Timeout time.Duration
+	Retry   RetryPolicy
 }
 
 // NewOTranslatorClient constructs the client.
@@ -38,6 +41,7 @@ func NewOTranslatorClient(apiKey string, opts OTranslatorOptions) *OTranslatorCl
 		httpClient: &http.Client{Timeout: timeout},
 		apiKey:     apiKey,
 		baseURL:    baseURL,
+		retry:      opts.Retry,
 	}
 }
 
@@ -56,6 +60,15 @@ type OTranslatorDocumentOptions struct {
 
 // TranslateDocument uploads a document for translation.
 func (c *OTranslatorClient) TranslateDocument(ctx context.Context, reader io.Reader, filename string, opts OTranslatorDocumentOptions) ([]byte, error) {
+	jobID, err := c.SubmitDocument(ctx, reader, filename, opts)
+	if err != nil {
+		return nil, err
+	}
+	return c.WaitJob(ctx, jobID)
+}
+
+// SubmitDocument uploads a document and returns the job ID.
+func (c *OTranslatorClient) SubmitDocument(ctx context.Context, reader io.Reader, filename string, opts OTranslatorDocumentOptions) (string, error) {
 	endpoint := fmt.Sprintf("%s/v1/document:translate", c.baseURL)
 
 	payload := &bytes.Buffer{}
@@ -63,49 +76,49 @@ func (c *OTranslatorClient) TranslateDocument(ctx context.Context, reader io.Rea
 
 	fileWriter, err := writer.CreateFormFile("file", filename)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 	if _, err := io.Copy(fileWriter, reader); err != nil {
-		return nil, err
+		return "", err
 	}
 
 	optsBytes, err := json.Marshal(opts)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 	if err := writer.WriteField("options", string(optsBytes)); err != nil {
-		return nil, err
+		return "", err
 	}
 
 	if err := writer.Close(); err != nil {
-		return nil, err
+		return "", err
 	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, payload)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 	req.Header.Set("Content-Type", writer.FormDataContentType())
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		return nil, parseOTranslatorError(resp.Body)
+		return "", parseOTranslatorError(resp.StatusCode, resp.Header, resp.Body)
 	}
 
 	var job struct {
 		JobID string `json:"job_id"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&job); err != nil {
-		return nil, err
+		return "", err
 	}
 
-	return c.pollJob(ctx, job.JobID)
+	return job.JobID, nil
 }
 
 // TranslateText translates plain text via OTranslator.
@@ -128,14 +141,14 @@ func (c *OTranslatorClient) TranslateText(ctx context.Context, text string, opts
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return "", err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		return "", parseOTranslatorError(resp.Body)
+		return "", parseOTranslatorError(resp.StatusCode, resp.Header, resp.Body)
 	}
 
 	var result struct {
@@ -147,7 +160,8 @@ func (c *OTranslatorClient) TranslateText(ctx context.Context, text string, opts
 	return result.Translation, nil
 }
 
-func (c *OTranslatorClient) pollJob(ctx context.Context, jobID string) ([]byte, error) {
+// WaitJob polls a job until it completes and downloads the result.
+func (c *OTranslatorClient) WaitJob(ctx context.Context, jobID string) ([]byte, error) {
 	endpoint := fmt.Sprintf("%s/v1/jobs/%s", c.baseURL, jobID)
 	ticker := time.NewTicker(5 * time.Second)
 	defer ticker.Stop()
@@ -163,7 +177,7 @@ func (c *OTranslatorClient) pollJob(ctx context.Context, jobID string) ([]byte,
 			}
 			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
 
-			resp, err := c.httpClient.Do(req)
+			resp, err := c.do(req)
 			if err != nil {
 				return nil, err
 			}
@@ -174,7 +188,7 @@ func (c *OTranslatorClient) pollJob(ctx context.Context, jobID string) ([]byte,
 			}
 
 			if resp.StatusCode >= 400 {
-				return nil, parseOTranslatorError(bytes.NewReader(body))
+				return nil, parseOTranslatorError(resp.StatusCode, resp.Header, bytes.NewReader(body))
 			}
 
 			var status struct {
@@ -210,35 +224,147 @@ func (c *OTranslatorClient) download(ctx context.Context, url string) ([]byte, e
 	}
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		return nil, parseOTranslatorError(resp.Body)
+		return nil, parseOTranslatorError(resp.StatusCode, resp.Header, resp.Body)
 	}
 
 	return io.ReadAll(resp.Body)
 }
 
-type oTranslatorError struct {
-	Message string `json:"message"`
-	Code    string `json:"code"`
+// OTranslatorError represents API errors. It unwraps to a typed provider
+// error such as ErrRateLimited.
+type OTranslatorError struct {
+	StatusCode int           `json:"-"`
+	Message    string        `json:"message"`
+	Code       string        `json:"code"`
+	RetryAfter time.Duration `json:"-"`
 }
 
-func (e *oTranslatorError) Error() string {
+func (e *OTranslatorError) Error() string {
 	return fmt.Sprintf("otranslator api error: %s (%s)", e.Message, e.Code)
 }
 
-func parseOTranslatorError(body io.Reader) error {
-	var apiErr oTranslatorError
-	if err := json.NewDecoder(body).Decode(&apiErr); err != nil {
-		return err
-	}
+// HTTPStatus returns the response status that produced the error.
+func (e *OTranslatorError) HTTPStatus() int {
+	return e.StatusCode
+}
+
+// Unwrap returns the typed provider error for the status.
+func (e *OTranslatorError) Unwrap() error {
+	return statusError(e.StatusCode)
+}
+
+func (c *OTranslatorClient) do(req *http.Request) (*http.Response, error) {
+	return sendWithRetry(c.httpClient, c.retry, req, isIdempotentMethod(req.Method))
+}
+
+func parseOTranslatorError(status int, header http.Header, body io.Reader) error {
+	apiErr := OTranslatorError{StatusCode: status, RetryAfter: parseRetryAfter(header)}
+	_ = json.NewDecoder(body).Decode(&apiErr)
 	if apiErr.Message == "" {
 		apiErr.Message = "unexpected error"
 	}
 	return &apiErr
 }
+
+// Health checks API availability.
+func (c *OTranslatorClient) Health(ctx context.Context) error {
+	endpoint := fmt.Sprintf("%s/v1/health", c.baseURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
+
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return parseOTranslatorError(resp.StatusCode, resp.Header, resp.Body)
+	}
+	return nil
+}
+
+// OTranslatorProvider adapts OTranslatorClient to the Provider interface.
+type OTranslatorProvider struct {
+	client *OTranslatorClient
+}
+
+// NewOTranslatorProvider wraps an OTranslator client.
+func NewOTranslatorProvider(client *OTranslatorClient) *OTranslatorProvider {
+	return &OTranslatorProvider{client: client}
+}
+
+// Type implements Provider.
+func (p *OTranslatorProvider) Type() ProviderType {
+	return ProviderOTranslator
+}
+
+// Capabilities implements Provider.
+func (p *OTranslatorProvider) Capabilities() Capabilities {
+	return Capabilities{
+		Documents: true,
+		Text:      true,
+		Formality: true,
+		Glossary:  true,
+		Images:    true,
+		XMLTags:   true,
+	}
+}
+
+// TranslateDocument implements Provider. A job submitted by an earlier
+// attempt is resumed unless OTranslator no longer knows it.
+func (p *OTranslatorProvider) TranslateDocument(ctx context.Context, reader io.Reader, req DocumentRequest) ([]byte, error) {
+	if job := req.resumable(ProviderOTranslator); job != nil {
+		result, err := p.client.WaitJob(ctx, job.ID)
+		if !errors.Is(err, ErrNotFound) {
+			return result, err
+		}
+	}
+	jobID, err := p.client.SubmitDocument(ctx, reader, req.FileName, p.options(req.SourceLang, req.TargetLang, req.Engine, req.Formality, req.GlossaryID, req.Passes, req.IgnoreComments))
+	if err != nil {
+		return nil, err
+	}
+	req.submitted(RemoteJob{Provider: ProviderOTranslator, Engine: req.Engine, ID: jobID})
+	return p.client.WaitJob(ctx, jobID)
+}
+
+// TranslateText implements Provider.
+func (p *OTranslatorProvider) TranslateText(ctx context.Context, text string, req TextRequest) (string, error) {
+	return p.client.TranslateText(ctx, text, p.options(req.SourceLang, req.TargetLang, req.Engine, req.Formality, req.GlossaryID, req.Passes, false))
+}
+
+// Languages implements LanguageSource with the static list.
+func (p *OTranslatorProvider) Languages(ctx context.Context) (*LanguageSet, error) {
+	set := StaticLanguages
+	return &set, nil
+}
+
+// Health implements Provider.
+func (p *OTranslatorProvider) Health(ctx context.Context) error {
+	return p.client.Health(ctx)
+}
+
+func (p *OTranslatorProvider) options(sourceLang, targetLang, engine, formality, glossaryID string, passes int, ignoreComments bool) OTranslatorDocumentOptions {
+	if passes < 1 {
+		passes = 1
+	}
+	return OTranslatorDocumentOptions{
+		SourceLang:     sourceLang,
+		TargetLang:     targetLang,
+		Model:          engine,
+		Passes:         passes,
+		Formality:      formality,
+		GlossaryID:     glossaryID,
+		IgnoreComments: ignoreComments,
+		PreserveFormat: true,
+	}
+}
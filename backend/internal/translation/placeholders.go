@@ -0,0 +1,275 @@
+package translation
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Placeholder markers replace protected tokens while text is at a provider.
+// The brackets are rare in real text and kept verbatim by MT engines.
+const (
+	placeholderOpen  = "⟦"
+	placeholderClose = "⟧"
+)
+
+var (
+	placeholderMarkerPattern = regexp.MustCompile(`⟦\s*(\d+)\s*⟧`)
+	// {{count}} (Mustache, i18next, Angular)
+	doubleBracePattern = regexp.MustCompile(`^\{\{[^{}]*\}\}`)
+	// ${name} (template literals)
+	templatePattern = regexp.MustCompile(`^\$\{[^{}]*\}`)
+	// {0}, {name}, {amount, number} (ICU and .NET arguments)
+	argumentPattern = regexp.MustCompile(`^\{\s*[\w.-]+\s*(?:,[^{}]*)?\}`)
+	// %s, %1$d, %.2f, %@, %% and Python's %(name)s
+	printfPattern = regexp.MustCompile(`^%(?:\(\w+\)|\d+\$)?[-+0#]*(?:\d+|\*)?(?:\.\d+)?(?:hh|h|ll|l|L|q|j|z|t)?[diouxXeEfFgGaAcspnr@%]`)
+	// The opening of an ICU plural/select block up to its first message.
+	icuStartPattern = regexp.MustCompile(`^\{\s*[\w.-]+\s*,\s*(?:plural|select|selectordinal)\s*,\s*(?:offset:\d+\s*)?(?:=\d+|[\w-]+)\s*\{`)
+	// The end of an ICU message followed by the next selector.
+	icuNextPattern = regexp.MustCompile(`^\}\s*(?:=\d+|[\w-]+)\s*\{`)
+	// The end of the last ICU message and of its block.
+	icuEndPattern   = regexp.MustCompile(`^\}\s*\}`)
+	inlineTagPrefix = regexp.MustCompile(`^` + inlineTagPattern.String())
+	// <code>…</code> in markup and `code` spans in Markdown stay verbatim.
+	codeElementPattern = regexp.MustCompile(`(?is)<code(?:\s[^>]*)?>.*?</code>`)
+	codeElementPrefix  = regexp.MustCompile(`^` + codeElementPattern.String())
+	codeSpanPattern    = regexp.MustCompile("^(?:``[^\n]+?``|`[^`\n]+`)")
+	// Bare URLs, without trailing sentence punctuation.
+	urlPattern = regexp.MustCompile(`^https?://[^\s<>"'()\[\]]*[^\s<>"'()\[\].,;:!?]`)
+	// The target of a Markdown link or image: ](url "title")
+	linkTargetPattern = regexp.MustCompile(`^\]\(\s*<?[^()\s<>]*>?(?:\s+"[^"]*")?\s*\)`)
+)
+
+// Masked is text whose placeholders and inline markup were replaced by
+// numbered markers.
+type Masked struct {
+	Text   string
+	tokens []string
+}
+
+// MaskPlaceholders protects the placeholders of software strings: printf
+// and .NET/ICU arguments, {{mustache}} and ${template} variables, inline
+// markup and the syntax of ICU plural/select blocks. Inline code, URLs and
+// Markdown link targets are protected too. The messages inside ICU blocks
+// and the text of links stay translatable.
+func MaskPlaceholders(text string) Masked {
+	var builder strings.Builder
+	var tokens []string
+	icuDepth := 0
+	for i := 0; i < len(text); {
+		rest := text[i:]
+		token := ""
+		switch rest[0] {
+		case '{':
+			if token = doubleBracePattern.FindString(rest); token == "" {
+				if token = icuStartPattern.FindString(rest); token != "" {
+					icuDepth++
+				} else {
+					token = argumentPattern.FindString(rest)
+				}
+			}
+		case '}':
+			if icuDepth > 0 {
+				if token = icuNextPattern.FindString(rest); token == "" {
+					if token = icuEndPattern.FindString(rest); token != "" {
+						icuDepth--
+					}
+				}
+			}
+		case '#':
+			// The plural count inside an ICU message.
+			if icuDepth > 0 {
+				token = "#"
+			}
+		case '%':
+			token = printfPattern.FindString(rest)
+		case '$':
+			token = templatePattern.FindString(rest)
+		case '<':
+			if token = codeElementPrefix.FindString(rest); token == "" {
+				token = inlineTagPrefix.FindString(rest)
+			}
+		case '`':
+			token = codeSpanPattern.FindString(rest)
+		case 'h':
+			if i == 0 || !isWordByte(text[i-1]) {
+				token = urlPattern.FindString(rest)
+			}
+		case ']':
+			token = linkTargetPattern.FindString(rest)
+		}
+		if token == "" {
+			builder.WriteByte(rest[0])
+			i++
+			continue
+		}
+		tokens = append(tokens, token)
+		builder.WriteString(placeholderOpen + strconv.Itoa(len(tokens)) + placeholderClose)
+		i += len(token)
+	}
+	if len(tokens) == 0 {
+		return Masked{Text: text}
+	}
+	return Masked{Text: builder.String(), tokens: tokens}
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'A' && b <= 'Z' || b >= 'a' && b <= 'z'
+}
+
+// Restore replaces the markers in translated by the tokens they protect. It
+// returns a *PlaceholderError when markers were lost, duplicated or invented.
+func (m Masked) Restore(translated string) (string, error) {
+	if len(m.tokens) == 0 {
+		return translated, nil
+	}
+	counts := make([]int, len(m.tokens))
+	var unexpected []string
+	restored := placeholderMarkerPattern.ReplaceAllStringFunc(translated, func(marker string) string {
+		id, _ := strconv.Atoi(placeholderMarkerPattern.FindStringSubmatch(marker)[1])
+		if id < 1 || id > len(m.tokens) {
+			unexpected = append(unexpected, marker)
+			return ""
+		}
+		counts[id-1]++
+		return m.tokens[id-1]
+	})
+	issue := PlaceholderIssue{Unexpected: unexpected}
+	for i, count := range counts {
+		switch {
+		case count == 0:
+			issue.Missing = append(issue.Missing, m.tokens[i])
+		case count > 1:
+			issue.Duplicated = append(issue.Duplicated, m.tokens[i])
+		}
+	}
+	if len(issue.Missing)+len(issue.Duplicated)+len(issue.Unexpected) > 0 {
+		return restored, &PlaceholderError{Issues: []PlaceholderIssue{issue}}
+	}
+	return restored, nil
+}
+
+// PlaceholderIssue lists the placeholders of one segment that did not
+// survive translation.
+type PlaceholderIssue struct {
+	Segment    int
+	Missing    []string
+	Duplicated []string
+	Unexpected []string
+}
+
+// PlaceholderError is returned when a provider lost or duplicated protected
+// placeholders. Retrying would send the same text again, so it is permanent.
+type PlaceholderError struct {
+	Issues []PlaceholderIssue
+}
+
+func (e *PlaceholderError) Error() string {
+	issue := e.Issues[0]
+	var problems []string
+	if len(issue.Missing) > 0 {
+		problems = append(problems, "lost "+strings.Join(issue.Missing, " "))
+	}
+	if len(issue.Duplicated) > 0 {
+		problems = append(problems, "duplicated "+strings.Join(issue.Duplicated, " "))
+	}
+	if len(issue.Unexpected) > 0 {
+		problems = append(problems, "unknown markers "+strings.Join(issue.Unexpected, " "))
+	}
+	message := fmt.Sprintf("placeholder check failed in segment %d: %s", issue.Segment+1, strings.Join(problems, ", "))
+	if len(e.Issues) > 1 {
+		message += fmt.Sprintf(" (and %d more segments)", len(e.Issues)-1)
+	}
+	return message
+}
+
+// MaskSegments masks the placeholders of every segment.
+func MaskSegments(segments []string) ([]string, []Masked) {
+	texts := make([]string, len(segments))
+	masks := make([]Masked, len(segments))
+	for i, segment := range segments {
+		masks[i] = MaskPlaceholders(segment)
+		texts[i] = masks[i].Text
+	}
+	return texts, masks
+}
+
+// RestoreSegments restores the translated segments of MaskSegments and
+// reports every segment whose placeholders did not survive.
+func RestoreSegments(masks []Masked, targets []string) ([]string, error) {
+	restored := make([]string, len(targets))
+	var issues []PlaceholderIssue
+	for i, target := range targets {
+		text, err := masks[i].Restore(target)
+		if err != nil {
+			issue := err.(*PlaceholderError).Issues[0]
+			issue.Segment = i
+			issues = append(issues, issue)
+		}
+		restored[i] = text
+	}
+	if len(issues) > 0 {
+		return nil, &PlaceholderError{Issues: issues}
+	}
+	return restored, nil
+}
+
+// CheckDocumentPlaceholders compares the placeholders of each segment of a
+// document translated by a provider with those of its source, in any order.
+// Providers translate documents unmasked, so this is the only check they
+// get. Formats that cannot be reassembled are not checked, nor documents
+// whose segments no longer align with the source because the provider
+// merged or split paragraphs.
+func CheckDocumentPlaceholders(fileName, targetLang string, source, translated []byte) error {
+	if !CanReassemble(fileName) {
+		return nil
+	}
+	sources, err := DocumentSegments(fileName, source)
+	if err != nil {
+		return nil
+	}
+	targets, err := TranslatedSegments(fileName, targetLang, translated)
+	if err != nil || len(targets) != len(sources) {
+		return nil
+	}
+	var issues []PlaceholderIssue
+	for i := range sources {
+		if issue, ok := comparePlaceholders(sources[i], targets[i]); !ok {
+			issue.Segment = i
+			issues = append(issues, issue)
+		}
+	}
+	if len(issues) > 0 {
+		return &PlaceholderError{Issues: issues}
+	}
+	return nil
+}
+
+// comparePlaceholders checks that target holds the placeholders of source,
+// in any order, and reports the differences.
+func comparePlaceholders(source, target string) (PlaceholderIssue, bool) {
+	sourceTokens := MaskPlaceholders(source).tokens
+	expected := make(map[string]int)
+	for _, token := range sourceTokens {
+		expected[token]++
+	}
+	found := make(map[string]int)
+	var issue PlaceholderIssue
+	for _, token := range MaskPlaceholders(target).tokens {
+		found[token]++
+		switch {
+		case expected[token] == 0:
+			issue.Unexpected = append(issue.Unexpected, token)
+		case found[token] == expected[token]+1:
+			issue.Duplicated = append(issue.Duplicated, token)
+		}
+	}
+	for _, token := range sourceTokens {
+		if found[token] < expected[token] {
+			issue.Missing = append(issue.Missing, token)
+			found[token]++
+		}
+	}
+	return issue, len(issue.Missing)+len(issue.Duplicated)+len(issue.Unexpected) == 0
+}
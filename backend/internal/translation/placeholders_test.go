@@ -0,0 +1,108 @@
+package translation
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMaskPlaceholders(t *testing.T) {
+	cases := map[string][]string{
+		"Hello {{name}}, you have %d new %s":                               {"{{name}}", "%d", "%s"},
+		"Deleted {0} of {total, number} files":                             {"{0}", "{total, number}"},
+		"Click <b>here</b> or ${link}":                                     {"<b>", "</b>", "${link}"},
+		"Save 20% today, %1$s and %(count)s":                               {"%1$s", "%(count)s"},
+		"Run `go test` from <code>a <b>b</b></code>":                       {"`go test`", "<code>a <b>b</b></code>"},
+		"See https://example.com/docs. Or [the guide](guide.md \"Guide\")": {"https://example.com/docs", "](guide.md \"Guide\")"},
+	}
+	for input, expected := range cases {
+		masked := MaskPlaceholders(input)
+		if strings.Join(masked.tokens, "|") != strings.Join(expected, "|") {
+			t.Fatalf("%q: expected tokens %q got %q", input, expected, masked.tokens)
+		}
+		restored, err := masked.Restore(masked.Text)
+		if err != nil || restored != input {
+			t.Fatalf("%q: round trip gave %q, %v", input, restored, err)
+		}
+	}
+}
+
+func TestMaskICUKeepsMessagesTranslatable(t *testing.T) {
+	input := "{count, plural, =0 {No files} one {# file} other {# files}}"
+	masked := MaskPlaceholders(input)
+	if masked.Text != "⟦1⟧No files⟦2⟧⟦3⟧ file⟦4⟧⟦5⟧ files⟦6⟧" {
+		t.Fatalf("unexpected masked text %q", masked.Text)
+	}
+	restored, err := masked.Restore("⟦1⟧Keine Dateien⟦2⟧⟦3⟧ Datei⟦4⟧⟦5⟧ Dateien⟦6⟧")
+	if err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if restored != "{count, plural, =0 {Keine Dateien} one {# Datei} other {# Dateien}}" {
+		t.Fatalf("unexpected restored text %q", restored)
+	}
+}
+
+func TestRestoreReportsLostAndDuplicatedPlaceholders(t *testing.T) {
+	masked := MaskPlaceholders("Hello {{name}}, %d messages")
+	_, err := masked.Restore("Hallo ⟦1⟧ ⟦1⟧, Nachrichten ⟦7⟧")
+	var placeholderErr *PlaceholderError
+	if !errors.As(err, &placeholderErr) {
+		t.Fatalf("expected placeholder error, got %v", err)
+	}
+	issue := placeholderErr.Issues[0]
+	if len(issue.Missing) != 1 || issue.Missing[0] != "%d" || len(issue.Duplicated) != 1 || len(issue.Unexpected) != 1 {
+		t.Fatalf("unexpected issue %+v", issue)
+	}
+}
+
+func TestTranslateSegmentsProtectsPlaceholders(t *testing.T) {
+	var sent []string
+	fn := func(_ context.Context, batch SegmentBatch) ([]string, error) {
+		sent = append(sent, batch.Segments...)
+		return upperBatch(batch), nil
+	}
+	targets, err := TranslateSegments(context.Background(), []string{"hello {{name}}", "plain"}, 100, 0, fn)
+	if err != nil {
+		t.Fatalf("translate: %v", err)
+	}
+	if sent[0] != "hello ⟦1⟧" {
+		t.Fatalf("placeholder reached the provider: %q", sent[0])
+	}
+	if targets[0] != "HELLO {{name}}" || targets[1] != "PLAIN" {
+		t.Fatalf("unexpected targets %q", targets)
+	}
+
+	drop := func(_ context.Context, batch SegmentBatch) ([]string, error) {
+		return []string{"hallo", "plain"}, nil
+	}
+	if _, err := TranslateSegments(context.Background(), []string{"hello {{name}}", "plain"}, 100, 0, drop); err == nil {
+		t.Fatal("expected lost placeholder to fail the translation")
+	}
+}
+
+func upperBatch(batch SegmentBatch) []string {
+	out := make([]string, len(batch.Segments))
+	for i, segment := range batch.Segments {
+		out[i] = strings.ToUpper(segment)
+	}
+	return out
+}
+
+func TestCheckDocumentPlaceholders(t *testing.T) {
+	source := []byte("Hello %s\nYou have {count} files\n")
+	if err := CheckDocumentPlaceholders("a.txt", "DE", source, []byte("Hallo %s\n{count} Dateien\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err := CheckDocumentPlaceholders("a.txt", "DE", source, []byte("Hallo %s %s\nSie haben Dateien\n"))
+	var placeholderErr *PlaceholderError
+	if !errors.As(err, &placeholderErr) || len(placeholderErr.Issues) != 2 {
+		t.Fatalf("expected issues in both segments, got %v", err)
+	}
+	if issue := placeholderErr.Issues[1]; issue.Segment != 1 || strings.Join(issue.Missing, "") != "{count}" {
+		t.Fatalf("unexpected issue %+v", issue)
+	}
+	if err := CheckDocumentPlaceholders("scan.pdf", "DE", []byte("%PDF"), []byte("%PDF")); err != nil {
+		t.Fatalf("expected unsupported formats to pass, got %v", err)
+	}
+}
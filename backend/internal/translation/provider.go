@@ -0,0 +1,191 @@
+package translation
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// Capabilities describes what a provider backend can do.
+type Capabilities struct {
+	Documents bool `json:"documents"`
+	Text      bool `json:"text"`
+	Formality bool `json:"formality"`
+	Glossary  bool `json:"glossary"`
+	Images    bool `json:"images"`
+	XMLTags   bool `json:"xmlTags"`
+}
+
+// DocumentRequest carries provider-neutral document translation parameters.
+type DocumentRequest struct {
+	FileName       string
+	SourceLang     string
+	TargetLang     string
+	Engine         string
+	Formality      string
+	GlossaryID     string
+	TagHandling    string
+	Passes         int
+	IgnoreComments bool
+	// Text holds the extracted plain text for providers without document
+	// support.
+	Text string
+	// Job is a remote job submitted by an earlier attempt. Providers with
+	// asynchronous document APIs resume it instead of uploading again.
+	Job *RemoteJob
+	// OnSubmit is called once a remote job has been accepted, before polling.
+	OnSubmit func(job RemoteJob)
+	// Memory maps normalized source segments to stored translations. Matched
+	// segments are not sent to the provider.
+	Memory map[string]string
+}
+
+// RemoteJob identifies a document job running at a provider.
+type RemoteJob struct {
+	Provider ProviderType
+	Engine   string
+	ID       string
+	// Key is a secret some providers require to poll the job.
+	Key string
+}
+
+// resumable returns the job of req belonging to providerType, if any.
+func (req DocumentRequest) resumable(providerType ProviderType) *RemoteJob {
+	if req.Job == nil || req.Job.Provider != providerType || req.Job.Engine != req.Engine {
+		return nil
+	}
+	return req.Job
+}
+
+// submitted reports a newly accepted job to req.OnSubmit.
+func (req DocumentRequest) submitted(job RemoteJob) {
+	if req.OnSubmit != nil {
+		req.OnSubmit(job)
+	}
+}
+
+// TextRequest carries provider-neutral text translation parameters.
+type TextRequest struct {
+	SourceLang string
+	TargetLang string
+	Engine     string
+	Formality  string
+	GlossaryID string
+	Passes     int
+}
+
+// Provider is implemented by every translation backend.
+type Provider interface {
+	Type() ProviderType
+	Capabilities() Capabilities
+	TranslateDocument(ctx context.Context, reader io.Reader, req DocumentRequest) ([]byte, error)
+	TranslateText(ctx context.Context, text string, req TextRequest) (string, error)
+	Health(ctx context.Context) error
+}
+
+// TextOutput is implemented by providers that return plain text instead of
+// the original format for some documents.
+type TextOutput interface {
+	ProducesText(fileName string) bool
+}
+
+// Registry resolves providers by type.
+type Registry struct {
+	mu        sync.RWMutex
+	providers map[ProviderType]Provider
+}
+
+// NewRegistry constructs a registry with the given providers.
+func NewRegistry(providers ...Provider) *Registry {
+	r := &Registry{providers: make(map[ProviderType]Provider)}
+	for _, p := range providers {
+		r.Register(p)
+	}
+	return r
+}
+
+// Register adds or replaces a provider.
+func (r *Registry) Register(p Provider) {
+	r.RegisterAs(p.Type(), p)
+}
+
+// RegisterAs binds a provider to another provider type, e.g. to route
+// DeepL models to the mock provider during offline development.
+func (r *Registry) RegisterAs(providerType ProviderType, p Provider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[providerType] = p
+}
+
+// Get returns the provider registered for the type.
+func (r *Registry) Get(providerType ProviderType) (Provider, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.providers[providerType]
+	if !ok {
+		return nil, fmt.Errorf("provider %s not registered", providerType)
+	}
+	return p, nil
+}
+
+// ForModel returns the provider backing the model.
+func (r *Registry) ForModel(model Model) (Provider, error) {
+	return r.Get(model.Provider)
+}
+
+// Types lists registered provider types in stable order.
+func (r *Registry) Types() []ProviderType {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	types := make([]ProviderType, 0, len(r.providers))
+	for t := range r.providers {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
+
+// ProducesText reports whether the route returns plain text rather than a
+// document in the format of fileName.
+func (r *Registry) ProducesText(route Route, fileName string) bool {
+	p, err := r.Get(route.Provider)
+	if err != nil {
+		return false
+	}
+	if !p.Capabilities().Documents {
+		return true
+	}
+	if out, ok := p.(TextOutput); ok {
+		return out.ProducesText(fileName)
+	}
+	return false
+}
+
+// RequiresText reports whether any route of the model returns plain text for
+// fileName and therefore needs the extracted document text.
+func (r *Registry) RequiresText(model Model, fileName string) bool {
+	for _, route := range model.Routes() {
+		if r.ProducesText(route, fileName) {
+			return true
+		}
+	}
+	return false
+}
+
+// Health checks every registered provider and returns errors keyed by type.
+func (r *Registry) Health(ctx context.Context) map[ProviderType]error {
+	r.mu.RLock()
+	providers := make(map[ProviderType]Provider, len(r.providers))
+	for t, p := range r.providers {
+		providers[t] = p
+	}
+	r.mu.RUnlock()
+
+	result := make(map[ProviderType]error, len(providers))
+	for t, p := range providers {
+		result[t] = p.Health(ctx)
+	}
+	return result
+}
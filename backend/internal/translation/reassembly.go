@@ -0,0 +1,437 @@
+package translation
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrTextOnlyProvider is returned when a text-level provider receives a
+// binary document it cannot reassemble and no extracted text.
+var ErrTextOnlyProvider = errors.New("provider translates text only")
+
+// SegmentFunc translates segments and returns one target per segment.
+type SegmentFunc func(ctx context.Context, segments []string) ([]string, error)
+
+// documentRewriter walks the text of a document in a stable order and
+// replaces every segment with the value returned by replace. Bilingual
+// formats keep the source and write the replacements for targetLang.
+type documentRewriter func(data []byte, targetLang string, replace func(string) string) ([]byte, error)
+
+var documentRewriters = map[string]documentRewriter{
+	".txt":       monolingual(rewriteLines),
+	".html":      monolingual(rewriteMarkup),
+	".htm":       monolingual(rewriteMarkup),
+	".xhtml":     monolingual(rewriteMarkup),
+	".md":        monolingual(rewriteMarkdown),
+	".markdown":  monolingual(rewriteMarkdown),
+	".docx":      monolingual(rewriteDocx),
+	".epub":      monolingual(rewriteEpub),
+	".json":      monolingual(rewriteJSON),
+	".xml":       monolingual(rewriteAndroidStrings),
+	".strings":   monolingual(rewriteAppleStrings),
+	".yaml":      monolingual(rewriteYAML),
+	".yml":       monolingual(rewriteYAML),
+	".po":        monolingual(rewriteGettext),
+	".pot":       monolingual(rewriteGettext),
+	".xcstrings": rewriteStringCatalog,
+	".srt":       monolingual(rewriteSubtitles),
+	".vtt":       monolingual(rewriteSubtitles),
+}
+
+// targetReaders list the translated segments of bilingual formats, whose
+// rewriters read the source side.
+var targetReaders = map[string]func(data []byte, targetLang string) ([]string, error){
+	".po":        gettextTargets,
+	".pot":       gettextTargets,
+	".xcstrings": stringCatalogTargets,
+}
+
+// monolingual adapts the rewriter of a format holding a single language.
+func monolingual(rewrite func(data []byte, replace func(string) string) ([]byte, error)) documentRewriter {
+	return func(data []byte, _ string, replace func(string) string) ([]byte, error) {
+		return rewrite(data, replace)
+	}
+}
+
+var (
+	docxParagraphPattern = regexp.MustCompile(`(?s)<w:p[ >].*?</w:p>`)
+	docxTextPattern      = regexp.MustCompile(`(?s)<w:t(\s[^>]*)?>(.*?)</w:t>`)
+	markupSkipPattern    = regexp.MustCompile(`(?is)^<(script|style|pre|textarea)[\s>]`)
+	// An inline code element or a tag inside a markup segment.
+	markupTokenPattern     = regexp.MustCompile(codeElementPattern.String() + `|` + inlineTagPattern.String())
+	markupAttributePattern = regexp.MustCompile(`(?i)(\s(?:alt|title)\s*=\s*)("[^"]*"|'[^']*')`)
+)
+
+// CanReassemble reports whether translations can be written back into the
+// layout of the file format.
+func CanReassemble(fileName string) bool {
+	_, ok := documentRewriters[strings.ToLower(filepath.Ext(fileName))]
+	return ok
+}
+
+// lossyFormats are rebuilt by merging the runs of each paragraph into its
+// first run, so inline formatting inside a paragraph is lost.
+var lossyFormats = map[string]bool{".docx": true, ".epub": true}
+
+// ReassemblesLosslessly reports whether a rewritten file keeps all the
+// formatting of the source. Other reassemblable formats are better left to
+// the provider's document translation.
+func ReassemblesLosslessly(fileName string) bool {
+	return CanReassemble(fileName) && !lossyFormats[strings.ToLower(filepath.Ext(fileName))]
+}
+
+// ReassembleDocument extracts the text segments of a document, translates
+// them in a single pass and writes the targets back in place, so layout,
+// styles and markup are kept. targetLang is the language the targets are
+// written as in bilingual formats.
+func ReassembleDocument(ctx context.Context, fileName, targetLang string, data []byte, translate SegmentFunc) ([]byte, error) {
+	rewrite, ok := documentRewriters[strings.ToLower(filepath.Ext(fileName))]
+	if !ok {
+		return nil, fmt.Errorf("cannot reassemble %s documents", filepath.Ext(fileName))
+	}
+	var segments []string
+	if _, err := rewrite(data, targetLang, func(s string) string {
+		segments = append(segments, s)
+		return s
+	}); err != nil {
+		return nil, err
+	}
+	if len(segments) == 0 {
+		return data, nil
+	}
+	targets, err := translate(ctx, segments)
+	if err != nil {
+		return nil, err
+	}
+	if len(targets) != len(segments) {
+		return nil, fmt.Errorf("expected %d translated segments, got %d", len(segments), len(targets))
+	}
+	next := 0
+	return rewrite(data, targetLang, func(string) string {
+		target := targets[next]
+		next++
+		return target
+	})
+}
+
+// ReviseDocument writes targets, one per source segment, into a translated
+// document. Lossless formats are regenerated from source; DOCX and EPUB are
+// patched in translated instead, so only paragraphs whose target changed
+// lose their inline formatting.
+func ReviseDocument(ctx context.Context, fileName, targetLang string, source, translated []byte, targets []string) ([]byte, error) {
+	data := source
+	if !ReassemblesLosslessly(fileName) {
+		data = translated
+	}
+	return ReassembleDocument(ctx, fileName, targetLang, data, func(context.Context, []string) ([]string, error) {
+		return targets, nil
+	})
+}
+
+// translateTextDocument serves TranslateDocument for text-level providers:
+// reassemblable formats keep their layout, everything else is translated as
+// the extracted (or UTF-8) text.
+func translateTextDocument(ctx context.Context, reader io.Reader, req DocumentRequest, maxChars, contextSegments int, fn BatchTranslateFunc) ([]byte, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	if CanReassemble(req.FileName) {
+		return ReassembleDocument(ctx, req.FileName, req.TargetLang, data, func(ctx context.Context, segments []string) ([]string, error) {
+			return TranslateSegments(ctx, segments, maxChars, contextSegments, fn)
+		})
+	}
+	text := req.Text
+	if text == "" {
+		if !utf8.Valid(data) {
+			return nil, ErrTextOnlyProvider
+		}
+		text = string(data)
+	}
+	translated, err := TranslateLines(ctx, text, maxChars, contextSegments, fn)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(translated), nil
+}
+
+// replaceTrimmed replaces the non-blank core of s and keeps the surrounding
+// whitespace. Blank strings are returned unchanged.
+func replaceTrimmed(s string, replace func(string) string) string {
+	trimmed := strings.TrimSpace(s)
+	if trimmed == "" {
+		return s
+	}
+	start := strings.Index(s, trimmed)
+	return s[:start] + replace(trimmed) + s[start+len(trimmed):]
+}
+
+func rewriteLines(data []byte, replace func(string) string) ([]byte, error) {
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		lines[i] = replaceTrimmed(line, replace)
+	}
+	return []byte(strings.Join(lines, "\n")), nil
+}
+
+// rewriteMarkup translates HTML/XHTML block by block: the text of a heading,
+// paragraph, list item or table cell is one segment with its inline markup
+// (links, emphasis, inline code) kept as tags, so sentences are not cut at
+// formatting. Alt and title attributes are translated on their own. Scripts,
+// styles, preformatted blocks and comments are left intact.
+func rewriteMarkup(data []byte, replace func(string) string) ([]byte, error) {
+	text := string(data)
+	var builder strings.Builder
+	var run markupRun
+	skipUntil := ""
+	for len(text) > 0 {
+		open := strings.IndexByte(text, '<')
+		if open < 0 {
+			open = len(text)
+		}
+		if skipUntil == "" {
+			run.addText(text[:open])
+		} else {
+			builder.WriteString(text[:open])
+		}
+		text = text[open:]
+		if text == "" {
+			break
+		}
+		end := strings.IndexByte(text, '>') + 1
+		if strings.HasPrefix(text, "<!--") {
+			if closing := strings.Index(text, "-->"); closing >= 0 {
+				end = closing + 3
+			}
+		}
+		if end <= 0 {
+			run.addText(text)
+			break
+		}
+		tag := text[:end]
+		text = text[end:]
+		if skipUntil != "" {
+			builder.WriteString(tag)
+			if strings.EqualFold(tag, skipUntil) {
+				skipUntil = ""
+			}
+			continue
+		}
+		if inlineElements[markupTagName(tag)] {
+			run.addTag(tag)
+			continue
+		}
+		builder.WriteString(run.flush(replace))
+		builder.WriteString(translateMarkupAttributes(tag, replace))
+		if m := markupSkipPattern.FindStringSubmatch(tag); m != nil && !strings.HasSuffix(tag, "/>") {
+			skipUntil = "</" + m[1] + ">"
+		}
+	}
+	builder.WriteString(run.flush(replace))
+	return []byte(builder.String()), nil
+}
+
+// inlineElements are the HTML elements that stay inside a segment.
+var inlineElements = map[string]bool{
+	"a": true, "abbr": true, "b": true, "bdi": true, "bdo": true, "br": true, "cite": true,
+	"code": true, "data": true, "del": true, "dfn": true, "em": true, "font": true, "i": true,
+	"img": true, "ins": true, "kbd": true, "mark": true, "q": true, "s": true, "samp": true,
+	"small": true, "span": true, "strong": true, "sub": true, "sup": true, "time": true,
+	"u": true, "var": true, "wbr": true,
+}
+
+func markupTagName(tag string) string {
+	name := strings.TrimLeft(tag, "</")
+	if end := strings.IndexAny(name, " \t\r\n/>"); end >= 0 {
+		name = name[:end]
+	}
+	return strings.ToLower(name)
+}
+
+// markupRun collects the text and inline tags of a block. raw is the markup
+// as written; segment is the text unescaped with the tags and inline code
+// elements kept verbatim.
+type markupRun struct {
+	raw       strings.Builder
+	segment   strings.Builder
+	tags      []string
+	codeDepth int
+	hasText   bool
+}
+
+func (r *markupRun) addText(text string) {
+	r.raw.WriteString(text)
+	if r.codeDepth > 0 {
+		r.segment.WriteString(text)
+		return
+	}
+	r.segment.WriteString(html.UnescapeString(text))
+	if strings.TrimSpace(text) != "" {
+		r.hasText = true
+	}
+}
+
+func (r *markupRun) addTag(tag string) {
+	r.raw.WriteString(tag)
+	r.segment.WriteString(tag)
+	r.tags = append(r.tags, tag)
+	if markupTagName(tag) == "code" && !strings.HasSuffix(tag, "/>") {
+		if strings.HasPrefix(tag, "</") {
+			r.codeDepth--
+		} else {
+			r.codeDepth++
+		}
+	}
+}
+
+// flush returns the translated block and resets the run.
+func (r *markupRun) flush(replace func(string) string) string {
+	out := r.raw.String()
+	if r.hasText {
+		segment := r.segment.String()
+		if translated := replaceTrimmed(segment, replace); translated != segment {
+			markup := make(map[string]bool)
+			for _, tag := range append(r.tags, codeElementPattern.FindAllString(segment, -1)...) {
+				markup[tag] = true
+			}
+			out = escapeMarkupText(translated, markup)
+		}
+	}
+	for _, tag := range r.tags {
+		if translated := translateMarkupAttributes(tag, replace); translated != tag {
+			out = strings.ReplaceAll(out, tag, translated)
+		}
+	}
+	*r = markupRun{}
+	return out
+}
+
+// escapeMarkupText escapes the text of a translated segment and keeps the
+// tags and inline code elements of its source, which are in markup. Text
+// that only looks like a tag is escaped.
+func escapeMarkupText(segment string, markup map[string]bool) string {
+	var builder strings.Builder
+	last := 0
+	for _, loc := range markupTokenPattern.FindAllStringIndex(segment, -1) {
+		if !markup[segment[loc[0]:loc[1]]] {
+			continue
+		}
+		builder.WriteString(html.EscapeString(segment[last:loc[0]]))
+		builder.WriteString(segment[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	builder.WriteString(html.EscapeString(segment[last:]))
+	return builder.String()
+}
+
+// translateMarkupAttributes translates the alt and title attributes of tag.
+func translateMarkupAttributes(tag string, replace func(string) string) string {
+	if strings.HasPrefix(tag, "<!") || strings.HasPrefix(tag, "</") {
+		return tag
+	}
+	return markupAttributePattern.ReplaceAllStringFunc(tag, func(attr string) string {
+		m := markupAttributePattern.FindStringSubmatch(attr)
+		quote := m[2][:1]
+		value := m[2][1 : len(m[2])-1]
+		return m[1] + quote + replaceTrimmed(value, func(s string) string {
+			return html.EscapeString(replace(html.UnescapeString(s)))
+		}) + quote
+	})
+}
+
+// rewriteDocx translates WordprocessingML paragraph by paragraph. The runs of
+// a paragraph are merged into its first run so sentences are not split at
+// formatting boundaries.
+func rewriteDocx(data []byte, replace func(string) string) ([]byte, error) {
+	return rewriteArchive(data, isDocxTextPart, func(content []byte) []byte {
+		return docxParagraphPattern.ReplaceAllFunc(content, func(paragraph []byte) []byte {
+			runs := docxTextPattern.FindAllSubmatch(paragraph, -1)
+			var text strings.Builder
+			for _, run := range runs {
+				text.WriteString(html.UnescapeString(string(run[2])))
+			}
+			if strings.TrimSpace(text.String()) == "" {
+				return paragraph
+			}
+			translated := replaceTrimmed(text.String(), replace)
+			if translated == text.String() {
+				return paragraph
+			}
+			first := true
+			return docxTextPattern.ReplaceAllFunc(paragraph, func([]byte) []byte {
+				if !first {
+					return []byte("<w:t></w:t>")
+				}
+				first = false
+				return []byte(`<w:t xml:space="preserve">` + html.EscapeString(translated) + "</w:t>")
+			})
+		})
+	})
+}
+
+func rewriteEpub(data []byte, replace func(string) string) ([]byte, error) {
+	return rewriteArchive(data, isMarkupPart, func(content []byte) []byte {
+		rewritten, _ := rewriteMarkup(content, replace)
+		return rewritten
+	})
+}
+
+func isDocxTextPart(name string) bool {
+	name = strings.ToLower(name)
+	if !strings.HasPrefix(name, "word/") || !strings.HasSuffix(name, ".xml") {
+		return false
+	}
+	base := strings.TrimPrefix(name, "word/")
+	return base == "document.xml" || base == "footnotes.xml" || base == "endnotes.xml" ||
+		strings.HasPrefix(base, "header") || strings.HasPrefix(base, "footer")
+}
+
+func isMarkupPart(name string) bool {
+	name = strings.ToLower(name)
+	return strings.HasSuffix(name, ".xhtml") || strings.HasSuffix(name, ".html") || strings.HasSuffix(name, ".htm")
+}
+
+// rewriteArchive copies a zip archive and transforms the matching entries.
+func rewriteArchive(data []byte, match func(name string) bool, transform func([]byte) []byte) ([]byte, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	out := &bytes.Buffer{}
+	writer := zip.NewWriter(out)
+	for _, f := range reader.File {
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		if match(f.Name) {
+			content = transform(content)
+		}
+		header := f.FileHeader
+		w, err := writer.CreateHeader(&header)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(content); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
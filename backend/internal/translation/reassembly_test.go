@@ -0,0 +1,106 @@
+package translation
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+func upperSegments(_ context.Context, segments []string) ([]string, error) {
+	out := make([]string, len(segments))
+	for i, seg := range segments {
+		out[i] = strings.ToUpper(seg)
+	}
+	return out, nil
+}
+
+func TestReassembleMarkup(t *testing.T) {
+	input := "<html><head><style>p { color: red; }</style></head><body><p> Fish &amp; chips </p><br/><p>tea</p></body></html>"
+	out, err := ReassembleDocument(context.Background(), "menu.html", "DE", []byte(input), upperSegments)
+	if err != nil {
+		t.Fatalf("reassemble: %v", err)
+	}
+	expected := "<html><head><style>p { color: red; }</style></head><body><p> FISH &amp; CHIPS </p><br/><p>TEA</p></body></html>"
+	if string(out) != expected {
+		t.Fatalf("expected %q got %q", expected, out)
+	}
+}
+
+func TestReassembleDocxMergesRuns(t *testing.T) {
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	w, _ := zw.Create("word/document.xml")
+	w.Write([]byte(`<w:body><w:p><w:pPr/><w:r><w:t>Hello </w:t></w:r><w:r><w:rPr><w:b/></w:rPr><w:t xml:space="preserve">big world</w:t></w:r></w:p><w:p><w:r><w:tab/></w:r></w:p></w:body>`))
+	zw.Close()
+
+	var seen []string
+	out, err := ReassembleDocument(context.Background(), "a.docx", "DE", buf.Bytes(), func(ctx context.Context, segments []string) ([]string, error) {
+		seen = segments
+		return upperSegments(ctx, segments)
+	})
+	if err != nil {
+		t.Fatalf("reassemble: %v", err)
+	}
+	if len(seen) != 1 || seen[0] != "Hello big world" {
+		t.Fatalf("unexpected segments %q", seen)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(out), int64(len(out)))
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	rc, _ := zr.File[0].Open()
+	content, _ := io.ReadAll(rc)
+	expected := `<w:body><w:p><w:pPr/><w:r><w:t xml:space="preserve">HELLO BIG WORLD</w:t></w:r><w:r><w:rPr><w:b/></w:rPr><w:t></w:t></w:r></w:p><w:p><w:r><w:tab/></w:r></w:p></w:body>`
+	if string(content) != expected {
+		t.Fatalf("unexpected document content %q", content)
+	}
+}
+
+func TestReviseDocxKeepsUneditedParagraphs(t *testing.T) {
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	w, _ := zw.Create("word/document.xml")
+	translated := `<w:p><w:r><w:t>Hallo </w:t></w:r><w:r><w:rPr><w:b/></w:rPr><w:t>Welt</w:t></w:r></w:p><w:p><w:r><w:t>Tee</w:t></w:r></w:p>`
+	w.Write([]byte(translated))
+	zw.Close()
+
+	out, err := ReviseDocument(context.Background(), "a.docx", "DE", nil, buf.Bytes(), []string{"Hallo Welt", "Kaffee"})
+	if err != nil {
+		t.Fatalf("revise: %v", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(out), int64(len(out)))
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	rc, _ := zr.File[0].Open()
+	content, _ := io.ReadAll(rc)
+	expected := `<w:p><w:r><w:t>Hallo </w:t></w:r><w:r><w:rPr><w:b/></w:rPr><w:t>Welt</w:t></w:r></w:p><w:p><w:r><w:t xml:space="preserve">Kaffee</w:t></w:r></w:p>`
+	if string(content) != expected {
+		t.Fatalf("unexpected document content %q", content)
+	}
+}
+
+func TestReassembleMarkupKeepsInlineMarkupInSegments(t *testing.T) {
+	input := "<h1>Setup</h1>\n<p>Run <code>make &amp;&amp; go</code> then <b>restart</b>.<br>Done &lt;now&gt;.</p>\n<img src=\"a.png\" alt=\"A cat\">\n<pre>keep me</pre>"
+	segments, err := DocumentSegments("guide.html", []byte(input))
+	if err != nil {
+		t.Fatalf("segments: %v", err)
+	}
+	expected := []string{"Setup", "Run <code>make &amp;&amp; go</code> then <b>restart</b>.<br>Done <now>.", "A cat"}
+	if strings.Join(segments, "|") != strings.Join(expected, "|") {
+		t.Fatalf("expected %q got %q", expected, segments)
+	}
+	out, err := ReassembleDocument(context.Background(), "guide.html", "DE", []byte(input), func(_ context.Context, segments []string) ([]string, error) {
+		return []string{"Einrichtung", "<b>Neustart</b> nach <code>make &amp;&amp; go</code>.<br>Fertig <jetzt>.", "Eine Katze"}, nil
+	})
+	if err != nil {
+		t.Fatalf("reassemble: %v", err)
+	}
+	want := "<h1>Einrichtung</h1>\n<p><b>Neustart</b> nach <code>make &amp;&amp; go</code>.<br>Fertig &lt;jetzt&gt;.</p>\n<img src=\"a.png\" alt=\"Eine Katze\">\n<pre>keep me</pre>"
+	if string(out) != want {
+		t.Fatalf("expected %q got %q", want, out)
+	}
+}
@@ -0,0 +1,221 @@
+package translation
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Typed provider errors. Provider error types unwrap to one of these so
+// callers can tell transient from permanent failures with errors.Is.
+var (
+	ErrRateLimited    = errors.New("provider rate limit exceeded")
+	ErrQuotaExceeded  = errors.New("provider quota exceeded")
+	ErrUnavailable    = errors.New("provider temporarily unavailable")
+	ErrUnauthorized   = errors.New("provider rejected credentials")
+	ErrInvalidRequest = errors.New("provider rejected request")
+	ErrNotFound       = errors.New("provider resource not found")
+	// ErrUnknownModel is returned for jobs whose model is not in the catalog.
+	ErrUnknownModel = errors.New("model not registered")
+	// ErrJobFailed is returned when a provider reports that it failed a
+	// remote job; resuming that job would only report the failure again.
+	ErrJobFailed = errors.New("provider failed the remote job")
+)
+
+// deepLTooManyRequests is DeepL's "too many requests, high load" status.
+const deepLTooManyRequests = 529
+
+// statusError maps an HTTP status to a typed provider error.
+func statusError(status int) error {
+	switch {
+	case status == deepLQuotaExceeded:
+		return ErrQuotaExceeded
+	case status == http.StatusTooManyRequests || status == deepLTooManyRequests:
+		return ErrRateLimited
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return ErrUnauthorized
+	case status == http.StatusNotFound:
+		return ErrNotFound
+	case status == http.StatusRequestTimeout || status >= 500:
+		return ErrUnavailable
+	default:
+		return ErrInvalidRequest
+	}
+}
+
+// IsRetryable reports whether running the job again may succeed. Only
+// failures that would repeat identically are permanent: requests the
+// provider rejected or failed, missing credentials or quota, lost
+// placeholders and unknown models. Everything else, from timeouts and cancellation to
+// truncated responses and storage errors, is retried; a remote job is
+// resumed on retry rather than submitted again.
+func IsRetryable(err error) bool {
+	return err != nil && !IsPermanent(err)
+}
+
+// IsPermanent reports whether err is a failure retrying cannot fix.
+func IsPermanent(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var placeholderErr *PlaceholderError
+	return errors.Is(err, ErrInvalidRequest) || errors.Is(err, ErrUnauthorized) ||
+		errors.Is(err, ErrQuotaExceeded) || errors.Is(err, ErrUnknownModel) ||
+		errors.Is(err, ErrTextOnlyProvider) || errors.Is(err, ErrJobFailed) ||
+		errors.As(err, &placeholderErr)
+}
+
+// RetryAfter returns the delay a provider asked for with its last error
+// response, or zero.
+func RetryAfter(err error) time.Duration {
+	var deepLErr *DeepLError
+	var oTranslatorErr *OTranslatorError
+	var llmErr *LLMError
+	var libreErr *LibreTranslateError
+	switch {
+	case errors.As(err, &deepLErr):
+		return deepLErr.RetryAfter
+	case errors.As(err, &oTranslatorErr):
+		return oTranslatorErr.RetryAfter
+	case errors.As(err, &llmErr):
+		return llmErr.RetryAfter
+	case errors.As(err, &libreErr):
+		return libreErr.RetryAfter
+	}
+	return 0
+}
+
+// RetryPolicy configures per-request retries of provider HTTP calls.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// DefaultRetryPolicy is used by clients constructed without a policy.
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 4, BaseDelay: 500 * time.Millisecond, MaxDelay: 30 * time.Second}
+
+func (p RetryPolicy) orDefault() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		return DefaultRetryPolicy
+	}
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = DefaultRetryPolicy.BaseDelay
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = DefaultRetryPolicy.MaxDelay
+	}
+	return p
+}
+
+// backoff returns the jittered exponential delay before attempt+1.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	ceiling := p.BaseDelay << uint(attempt-1)
+	if ceiling <= 0 || ceiling > p.MaxDelay {
+		ceiling = p.MaxDelay
+	}
+	// Equal jitter: half fixed, half random, so retries never fire at once.
+	half := ceiling / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+func retryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, deepLTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an
+// HTTP date.
+func parseRetryAfter(header http.Header) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(at); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
+
+// sendWithRetry sends req and retries rate limits and transient server
+// errors with jittered exponential backoff, honoring Retry-After. Transport
+// errors are only retried for idempotent requests, because a lost response
+// to an upload may still have been billed. The final response is returned
+// as is; callers parse error statuses.
+func sendWithRetry(client *http.Client, policy RetryPolicy, req *http.Request, idempotent bool) (*http.Response, error) {
+	policy = policy.orDefault()
+	ctx := req.Context()
+	for attempt := 1; ; attempt++ {
+		resp, err := client.Do(req)
+		if attempt >= policy.MaxAttempts || (req.Body != nil && req.GetBody == nil) {
+			return resp, err
+		}
+
+		var wait time.Duration
+		switch {
+		case err != nil:
+			if ctx.Err() != nil || !idempotent {
+				return nil, err
+			}
+			wait = policy.backoff(attempt)
+		case retryableStatus(resp.StatusCode):
+			retryAfter := parseRetryAfter(resp.Header)
+			if retryAfter > policy.MaxDelay {
+				// Waiting longer is the job queue's business.
+				return resp, nil
+			}
+			wait = policy.backoff(attempt)
+			if retryAfter > wait {
+				wait = retryAfter
+			}
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		default:
+			return resp, nil
+		}
+
+		if err := sleepContext(ctx, wait); err != nil {
+			return nil, err
+		}
+		next := req.Clone(ctx)
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			next.Body = body
+		}
+		req = next
+	}
+}
+
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
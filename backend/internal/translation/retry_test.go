@@ -0,0 +1,109 @@
+package translation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSendWithRetryHonorsRetryAfter(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("payload"))
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Second}
+	start := time.Now()
+	resp, err := sendWithRetry(server.Client(), policy, req, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || calls != 2 {
+		t.Fatalf("expected success on second call, got status %d after %d calls", resp.StatusCode, calls)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Fatalf("expected to wait for Retry-After, waited %s", elapsed)
+	}
+}
+
+func TestSendWithRetryReturnsPermanentErrors(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	resp, err := sendWithRetry(server.Client(), RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}, req, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if calls != 1 {
+		t.Fatalf("expected a single call, got %d", calls)
+	}
+}
+
+func TestTypedProviderErrors(t *testing.T) {
+	cases := []struct {
+		err       error
+		target    error
+		retryable bool
+	}{
+		{&DeepLError{StatusCode: http.StatusTooManyRequests}, ErrRateLimited, true},
+		{&DeepLError{StatusCode: deepLQuotaExceeded}, ErrQuotaExceeded, false},
+		{&OTranslatorError{StatusCode: http.StatusServiceUnavailable}, ErrUnavailable, true},
+		{&LLMError{StatusCode: http.StatusUnauthorized}, ErrUnauthorized, false},
+		{&LibreTranslateError{StatusCode: http.StatusBadRequest}, ErrInvalidRequest, false},
+	}
+	for _, tc := range cases {
+		wrapped := fmt.Errorf("translate: %w", tc.err)
+		if !errors.Is(wrapped, tc.target) {
+			t.Errorf("%v: expected %v", tc.err, tc.target)
+		}
+		if got := IsRetryable(wrapped); got != tc.retryable {
+			t.Errorf("%v: expected retryable %v got %v", tc.err, tc.retryable, got)
+		}
+	}
+	transient := []error{
+		context.Canceled,
+		fmt.Errorf("worker shutdown: %w", context.Canceled),
+		fmt.Errorf("decode response: %w", io.ErrUnexpectedEOF),
+		fmt.Errorf("read source: %w", errors.New("storage: connection reset")),
+		fmt.Errorf("poll: %w", context.DeadlineExceeded),
+	}
+	for _, err := range transient {
+		if !IsRetryable(err) || IsPermanent(err) {
+			t.Errorf("%v: expected a retryable error", err)
+		}
+	}
+	permanent := []error{
+		fmt.Errorf("job: %w", ErrUnknownModel),
+		fmt.Errorf("deepl: %w: %s", ErrJobFailed, "Source file is corrupted"),
+		fmt.Errorf("restore: %w", &PlaceholderError{Issues: []PlaceholderIssue{{Missing: []string{"%s"}}}}),
+	}
+	for _, err := range permanent {
+		if IsRetryable(err) || !IsPermanent(err) {
+			t.Errorf("%v: expected a permanent error", err)
+		}
+	}
+	if got := RetryAfter(fmt.Errorf("x: %w", &LLMError{StatusCode: 429, RetryAfter: time.Minute})); got != time.Minute {
+		t.Fatalf("expected Retry-After of one minute, got %s", got)
+	}
+}
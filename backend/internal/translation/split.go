@@ -0,0 +1,93 @@
+package translation
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// DocumentPart is a piece of an oversized document translated on its own.
+// Formats translated segment by segment have no part document: Sources
+// lists the part's segments and Targets receives their translations.
+type DocumentPart struct {
+	FileName string   `json:"fileName"`
+	Data     []byte   `json:"data,omitempty"`
+	Segments int      `json:"segments"`
+	Sources  []string `json:"sources,omitempty"`
+	Targets  []string `json:"targets,omitempty"`
+}
+
+// SplitDocument divides the segments of a reassemblable document into parts
+// of at most maxChars characters, breaking only between segments so no
+// paragraph is cut. Each part is an HTML document with one paragraph per
+// segment, which document and text providers both accept; Markdown,
+// localization and subtitle parts are segment lists instead, so they keep
+// the placeholder masking of their format. A document that fits yields a
+// single part.
+func SplitDocument(fileName string, data []byte, maxChars int) ([]DocumentPart, error) {
+	if !CanReassemble(fileName) {
+		return nil, fmt.Errorf("%s documents cannot be split", filepath.Ext(fileName))
+	}
+	segments, err := DocumentSegments(fileName, data)
+	if err != nil {
+		return nil, err
+	}
+	base := strings.TrimSuffix(filepath.Base(fileName), filepath.Ext(fileName))
+	chunks := ChunkSegments(segments, maxChars)
+	parts := make([]DocumentPart, 0, len(chunks))
+	bySegment := translatesBySegment(fileName)
+	for i, chunk := range chunks {
+		var builder strings.Builder
+		var sources []string
+		builder.WriteString("<!DOCTYPE html>\n<html><head><meta charset=\"utf-8\"></head><body>\n")
+		size := 0
+		for _, idx := range chunk {
+			size += utf8.RuneCountInString(segments[idx])
+			sources = append(sources, segments[idx])
+			builder.WriteString("<p>" + html.EscapeString(segments[idx]) + "</p>\n")
+		}
+		if maxChars > 0 && size > maxChars {
+			return nil, fmt.Errorf("segment %d has %d characters, more than the limit of %d", chunk[0], size, maxChars)
+		}
+		if bySegment {
+			parts = append(parts, DocumentPart{
+				FileName: fmt.Sprintf("%s.part%d%s", base, i+1, filepath.Ext(fileName)),
+				Segments: len(chunk),
+				Sources:  sources,
+			})
+			continue
+		}
+		builder.WriteString("</body></html>\n")
+		parts = append(parts, DocumentPart{
+			FileName: fmt.Sprintf("%s.part%d.html", base, i+1),
+			Data:     []byte(builder.String()),
+			Segments: len(chunk),
+		})
+	}
+	return parts, nil
+}
+
+// JoinDocument writes the translated parts of SplitDocument back into the
+// original document, keeping its layout.
+func JoinDocument(fileName, targetLang string, data []byte, parts []DocumentPart) ([]byte, error) {
+	var targets []string
+	for i, part := range parts {
+		segments := part.Targets
+		if part.Sources == nil {
+			var err error
+			if segments, err = DocumentSegments(part.FileName, part.Data); err != nil {
+				return nil, fmt.Errorf("part %d: %w", i+1, err)
+			}
+		}
+		if len(segments) != part.Segments {
+			return nil, fmt.Errorf("part %d: expected %d translated segments, got %d", i+1, part.Segments, len(segments))
+		}
+		targets = append(targets, segments...)
+	}
+	return ReassembleDocument(context.Background(), fileName, targetLang, data, func(_ context.Context, segments []string) ([]string, error) {
+		return targets, nil
+	})
+}
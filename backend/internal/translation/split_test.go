@@ -0,0 +1,75 @@
+package translation
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSplitAndJoinDocument(t *testing.T) {
+	input := "first line\n\nsecond <line> & more\nthird\n"
+	parts, err := SplitDocument("notes.txt", []byte(input), 25)
+	if err != nil {
+		t.Fatalf("split: %v", err)
+	}
+	if len(parts) != 2 || parts[0].Segments != 1 || parts[1].Segments != 2 {
+		t.Fatalf("unexpected parts %+v", parts)
+	}
+	if parts[1].FileName != "notes.part2.html" {
+		t.Fatalf("unexpected part name %q", parts[1].FileName)
+	}
+	for i := range parts {
+		translated, err := ReassembleDocument(context.Background(), parts[i].FileName, "", parts[i].Data, upperSegments)
+		if err != nil {
+			t.Fatalf("translate part %d: %v", i, err)
+		}
+		parts[i].Data = translated
+	}
+	out, err := JoinDocument("notes.txt", "DE", []byte(input), parts)
+	if err != nil {
+		t.Fatalf("join: %v", err)
+	}
+	if expected := strings.ToUpper(input); string(out) != expected {
+		t.Fatalf("expected %q got %q", expected, out)
+	}
+}
+
+func TestSplitDocumentRejectsOversizedSegment(t *testing.T) {
+	if _, err := SplitDocument("notes.txt", []byte("short\n"+strings.Repeat("x", 30)), 25); err == nil {
+		t.Fatal("expected an error for a segment above the limit")
+	}
+	if _, err := SplitDocument("scan.pdf", []byte("%PDF"), 25); err == nil {
+		t.Fatal("expected an error for a format that cannot be split")
+	}
+}
+
+func TestJoinDocumentDetectsLostSegments(t *testing.T) {
+	parts, err := SplitDocument("notes.txt", []byte("one\ntwo\n"), 100)
+	if err != nil {
+		t.Fatalf("split: %v", err)
+	}
+	parts[0].Data = []byte("<html><body><p>EINS ZWEI</p></body></html>")
+	if _, err := JoinDocument("notes.txt", "DE", []byte("one\ntwo\n"), parts); err == nil {
+		t.Fatal("expected a segment count mismatch")
+	}
+}
+
+func TestSplitLocalizationKeepsSegmentParts(t *testing.T) {
+	input := `{"a": "Hello {name}", "b": "Bye {name}"}`
+	parts, err := SplitDocument("en.json", []byte(input), 15)
+	if err != nil {
+		t.Fatalf("split: %v", err)
+	}
+	if len(parts) != 2 || parts[0].Data != nil || parts[0].Sources[0] != "Hello {name}" || parts[1].FileName != "en.part2.json" {
+		t.Fatalf("unexpected parts %+v", parts)
+	}
+	parts[0].Targets = []string{"Hallo {name}"}
+	parts[1].Targets = []string{"Tschüss {name}"}
+	out, err := JoinDocument("en.json", "DE", []byte(input), parts)
+	if err != nil {
+		t.Fatalf("join: %v", err)
+	}
+	if expected := `{"a": "Hallo {name}", "b": "Tschüss {name}"}`; string(out) != expected {
+		t.Fatalf("expected %q got %q", expected, out)
+	}
+}
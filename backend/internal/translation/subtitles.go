@@ -0,0 +1,481 @@
+package translation
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// subtitleLineChars is the usual broadcast limit for a subtitle line.
+	subtitleLineChars = 42
+	// subtitleSpacelessLineChars is the line limit for scripts written
+	// without spaces, whose characters are full width.
+	subtitleSpacelessLineChars = 16
+	// subtitleMaxCPS is the reading speed, in characters per second, above
+	// which a translated cue is kept on screen longer.
+	subtitleMaxCPS = 20
+	// subtitleMinGap is the pause kept before the next cue when a cue is
+	// extended.
+	subtitleMinGap = 80 * time.Millisecond
+	// subtitleMaxGroup caps how many cues one sentence may span before it is
+	// translated in pieces.
+	subtitleMaxGroup = 4
+	// subtitleSentenceGap is the pause after which cues are not joined into
+	// one sentence even without final punctuation.
+	subtitleSentenceGap = 2 * time.Second
+)
+
+var (
+	subtitleTimePattern = regexp.MustCompile(`(?:(\d+):)?(\d{1,2}):(\d{2})[,.](\d{3})`)
+	// An SSA override such as {\an8} positions the cue and is kept.
+	subtitleOverridePattern = regexp.MustCompile(`^(?:\{\\[^{}]*\})+`)
+	subtitleTagPattern      = regexp.MustCompile(`\{\\[^{}]*\}`)
+	subtitleBlockSeparator  = regexp.MustCompile(`\n[ \t]*\n`)
+)
+
+// subtitleCue is a cue of an SRT or WebVTT file. lines are the lines of its
+// block; the lines from text on are the displayed text.
+type subtitleCue struct {
+	lines    []string
+	text     int
+	start    time.Duration
+	end      time.Duration
+	override string
+	// translated cues get their reading speed checked; dropped cues were
+	// left without text and merged into the cue before them.
+	translated bool
+	dropped    bool
+}
+
+func (c subtitleCue) isCue() bool {
+	return c.text > 0 && c.text < len(c.lines)
+}
+
+// content returns the displayed text of the cue on one line, without its
+// positioning override.
+func (c subtitleCue) content() string {
+	text := strings.Join(strings.Fields(strings.Join(c.lines[c.text:], " ")), " ")
+	return strings.TrimSpace(strings.TrimPrefix(text, c.override))
+}
+
+// dialogue reports whether every line of the cue is a speaker turn
+// ("- Hi." / "- Hello."), whose lines are translated on their own.
+func (c subtitleCue) dialogue() bool {
+	if len(c.lines)-c.text < 2 {
+		return false
+	}
+	for _, line := range c.lines[c.text:] {
+		if !strings.HasPrefix(strings.TrimSpace(line), "-") {
+			return false
+		}
+	}
+	return true
+}
+
+// setEnd moves the end of the cue, written in the layout of its timing line.
+func (c *subtitleCue) setEnd(end time.Duration) {
+	line := c.lines[c.text-1]
+	loc := subtitleTimePattern.FindAllStringIndex(line, 2)
+	if len(loc) < 2 {
+		return
+	}
+	c.lines[c.text-1] = line[:loc[1][0]] + formatSubtitleTime(end, line[loc[1][0]:loc[1][1]]) + line[loc[1][1]:]
+	c.end = end
+}
+
+// IsSubtitleFile reports whether fileName is an SRT or WebVTT file.
+func IsSubtitleFile(fileName string) bool {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".srt", ".vtt":
+		return true
+	}
+	return false
+}
+
+// rewriteSubtitles translates the cues of SRT and WebVTT files and keeps
+// numbering, timecodes, cue settings, styles and notes. A sentence running
+// over several cues is translated as one segment and spread back over its
+// cues by their display time, so reading speed stays even; the text of each
+// cue is wrapped to subtitleLineChars per line. A cue left without text is
+// merged into the cue before it, and translated cues read faster than
+// subtitleMaxCPS stay on screen longer where the next cue allows it.
+func rewriteSubtitles(data []byte, replace func(string) string) ([]byte, error) {
+	text := strings.TrimPrefix(string(data), "\uFEFF")
+	bom := len(text) != len(data)
+	newline := "\n"
+	if strings.Contains(text, "\r\n") {
+		newline = "\r\n"
+		text = strings.ReplaceAll(text, "\r\n", "\n")
+	}
+	body := strings.TrimRight(text, "\n")
+	blocks := parseSubtitles(body)
+	for _, group := range groupSubtitleCues(blocks) {
+		if len(group) == 1 && blocks[group[0]].dialogue() {
+			cue := &blocks[group[0]]
+			for i := cue.text; i < len(cue.lines); i++ {
+				cue.lines[i] = replaceTrimmed(cue.lines[i], replace)
+			}
+			continue
+		}
+		parts := make([]string, len(group))
+		for i, idx := range group {
+			parts[i] = blocks[idx].content()
+		}
+		source := strings.Join(parts, " ")
+		translated := replace(source)
+		if translated == source || strings.TrimSpace(translated) == "" {
+			continue
+		}
+		pieces := distributeSubtitleText(translated, subtitleWeights(blocks, group))
+		last := &blocks[group[0]]
+		for i, idx := range group {
+			cue := &blocks[idx]
+			if pieces[i] == "" {
+				last.setEnd(cue.end)
+				cue.dropped = true
+				continue
+			}
+			lines := wrapSubtitleLine(pieces[i], subtitleLineLimit(pieces[i]))
+			lines[0] = cue.override + lines[0]
+			cue.lines = append(cue.lines[:cue.text], lines...)
+			cue.translated = true
+			last = cue
+		}
+	}
+	extendFastCues(blocks)
+	renumber := false
+	for _, block := range blocks {
+		renumber = renumber || block.dropped
+	}
+	out := make([]string, 0, len(blocks))
+	number := 0
+	for _, block := range blocks {
+		if block.dropped {
+			continue
+		}
+		if block.isCue() {
+			number++
+			if renumber && block.text > 1 && isSubtitleNumber(block.lines[0]) {
+				block.lines[0] = strconv.Itoa(number)
+			}
+		}
+		out = append(out, strings.Join(block.lines, "\n"))
+	}
+	result := strings.ReplaceAll(strings.Join(out, "\n\n")+text[len(body):], "\n", newline)
+	if bom {
+		result = "\uFEFF" + result
+	}
+	return []byte(result), nil
+}
+
+// parseSubtitles splits a subtitle file into blank-line separated blocks.
+// Blocks without a timing line (the WebVTT header, NOTE, STYLE and REGION
+// blocks) are kept as they are.
+func parseSubtitles(text string) []subtitleCue {
+	var blocks []subtitleCue
+	for _, raw := range subtitleBlockSeparator.Split(text, -1) {
+		block := subtitleCue{lines: strings.Split(raw, "\n")}
+		for i, line := range block.lines {
+			if !strings.Contains(line, "-->") {
+				continue
+			}
+			times := subtitleTimePattern.FindAllStringSubmatch(line, 2)
+			if len(times) == 2 && !strings.HasPrefix(block.lines[0], "NOTE") {
+				block.start = parseSubtitleTime(times[0])
+				block.end = parseSubtitleTime(times[1])
+				block.text = i + 1
+				if block.isCue() {
+					block.override = subtitleOverridePattern.FindString(strings.TrimSpace(block.lines[block.text]))
+				}
+			}
+			break
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+func isSubtitleNumber(line string) bool {
+	_, err := strconv.Atoi(strings.TrimSpace(line))
+	return err == nil
+}
+
+// formatSubtitleTime writes d in the layout of the timestamp like: SRT or
+// WebVTT separator, with hours when like has them or d needs them.
+func formatSubtitleTime(d time.Duration, like string) string {
+	separator := "."
+	if strings.Contains(like, ",") {
+		separator = ","
+	}
+	hours, minutes := d/time.Hour, d%time.Hour/time.Minute
+	seconds, millis := d%time.Minute/time.Second, d%time.Second/time.Millisecond
+	if hours > 0 || strings.Count(like, ":") == 2 {
+		return fmt.Sprintf("%02d:%02d:%02d%s%03d", hours, minutes, seconds, separator, millis)
+	}
+	return fmt.Sprintf("%02d:%02d%s%03d", minutes, seconds, separator, millis)
+}
+
+func parseSubtitleTime(match []string) time.Duration {
+	var total time.Duration
+	units := []time.Duration{time.Hour, time.Minute, time.Second, time.Millisecond}
+	for i, unit := range units {
+		if value, err := strconv.Atoi(match[i+1]); err == nil {
+			total += time.Duration(value) * unit
+		}
+	}
+	return total
+}
+
+// groupSubtitleCues joins consecutive cues into sentences: a group ends at
+// final punctuation, a dialogue cue, a long pause or subtitleMaxGroup cues.
+func groupSubtitleCues(blocks []subtitleCue) [][]int {
+	var groups [][]int
+	var current []int
+	closeGroup := func() {
+		if len(current) > 0 {
+			groups = append(groups, current)
+			current = nil
+		}
+	}
+	for i, block := range blocks {
+		if !block.isCue() || block.content() == "" {
+			continue
+		}
+		if block.dialogue() {
+			closeGroup()
+			groups = append(groups, []int{i})
+			continue
+		}
+		if len(current) > 0 && block.start-blocks[current[len(current)-1]].end > subtitleSentenceGap {
+			closeGroup()
+		}
+		current = append(current, i)
+		if endsSentence(stripSubtitleTags(block.content())) || len(current) == subtitleMaxGroup {
+			closeGroup()
+		}
+	}
+	closeGroup()
+	return groups
+}
+
+func endsSentence(text string) bool {
+	text = strings.TrimRight(text, `"')]»”’ `)
+	last, _ := utf8.DecodeLastRuneInString(text)
+	return strings.ContainsRune(".!?…♪。！？:;", last)
+}
+
+// subtitleWeights returns the share of a group's text each cue receives:
+// its display time, or its source length when timings are missing.
+func subtitleWeights(blocks []subtitleCue, group []int) []float64 {
+	weights := make([]float64, len(group))
+	for i, idx := range group {
+		weights[i] = float64(blocks[idx].end - blocks[idx].start)
+		if weights[i] <= 0 {
+			for j, idx := range group {
+				weights[j] = float64(utf8.RuneCountInString(blocks[idx].content()))
+			}
+			break
+		}
+	}
+	return weights
+}
+
+// distributeSubtitleText splits text at word boundaries into one piece per
+// weight, each close to its share of the characters. Scripts without spaces
+// are split between characters. Text with fewer words than weights gives one
+// word to each leading piece and leaves the rest empty.
+func distributeSubtitleText(text string, weights []float64) []string {
+	pieces := make([]string, len(weights))
+	if len(weights) == 1 {
+		pieces[0] = text
+		return pieces
+	}
+	words := strings.Fields(text)
+	separator := " "
+	if len(words) < len(weights) && spacelessScript(text) {
+		words = nil
+		for _, r := range text {
+			if !unicode.IsSpace(r) {
+				words = append(words, string(r))
+			}
+		}
+		separator = ""
+	}
+	if len(words) <= len(weights) {
+		copy(pieces, words)
+		return pieces
+	}
+	// Sizes count the visible characters; markup takes no screen space.
+	sizes := make([]float64, len(words))
+	textLen := 0.0
+	for i, word := range words {
+		sizes[i] = float64(utf8.RuneCountInString(stripSubtitleTags(word)) + len(separator))
+		textLen += sizes[i]
+	}
+	total := 0.0
+	for _, weight := range weights {
+		total += weight
+	}
+	cumulative, taken := 0.0, 0.0
+	next := 0
+	for i := range weights {
+		if i == len(weights)-1 {
+			pieces[i] = strings.Join(words[next:], separator)
+			break
+		}
+		cumulative += weights[i]
+		target := textLen * cumulative / total
+		end := next
+		// Leave at least one word for every later cue.
+		limit := len(words) - (len(weights) - 1 - i)
+		for end < limit {
+			if end > next && taken+sizes[end]/2 > target {
+				break
+			}
+			taken += sizes[end]
+			end++
+		}
+		pieces[i] = strings.Join(words[next:end], separator)
+		next = end
+	}
+	return pieces
+}
+
+// wrapSubtitleLine breaks text into lines of at most limit characters,
+// preferring two lines of balanced length. Scripts without spaces are broken
+// between characters.
+func wrapSubtitleLine(text string, limit int) []string {
+	if utf8.RuneCountInString(text) <= limit {
+		return []string{text}
+	}
+	if spacelessScript(text) {
+		return wrapSpacelessLine(text, limit)
+	}
+	words := strings.Fields(text)
+	best, bestDiff := -1, 0
+	for i := 1; i < len(words); i++ {
+		first := utf8.RuneCountInString(strings.Join(words[:i], " "))
+		second := utf8.RuneCountInString(strings.Join(words[i:], " "))
+		if first > limit || second > limit {
+			continue
+		}
+		diff := first - second
+		if diff < 0 {
+			diff = -diff
+		}
+		if best < 0 || diff < bestDiff {
+			best, bestDiff = i, diff
+		}
+	}
+	if best > 0 {
+		return []string{strings.Join(words[:best], " "), strings.Join(words[best:], " ")}
+	}
+	var lines []string
+	line := ""
+	for _, word := range words {
+		if line != "" && utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) > limit {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	return append(lines, line)
+}
+
+// wrapSpacelessLine breaks text into as few lines of equal length as the
+// limit allows. Closing punctuation stays at the end of its line.
+func wrapSpacelessLine(text string, limit int) []string {
+	runes := []rune(text)
+	count := (len(runes) + limit - 1) / limit
+	size := (len(runes) + count - 1) / count
+	var lines []string
+	for start := 0; start < len(runes); {
+		end := start + size
+		if end >= len(runes) {
+			lines = append(lines, string(runes[start:]))
+			break
+		}
+		for end < len(runes) && strings.ContainsRune("、。，．！？：；」』）〕】…ー", runes[end]) {
+			end++
+		}
+		lines = append(lines, string(runes[start:end]))
+		start = end
+	}
+	return lines
+}
+
+// subtitleLineLimit returns the number of characters a line of text holds.
+func subtitleLineLimit(text string) int {
+	if spacelessScript(text) {
+		return subtitleSpacelessLineChars
+	}
+	return subtitleLineChars
+}
+
+// spacelessScript reports whether text is written in a script that does not
+// separate words with spaces.
+func spacelessScript(text string) bool {
+	for _, r := range text {
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Thai, unicode.Lao, unicode.Khmer, unicode.Myanmar) {
+			return true
+		}
+	}
+	return false
+}
+
+// extendFastCues keeps translated cues read faster than subtitleMaxCPS on
+// screen longer, up to subtitleMinGap before the next cue.
+func extendFastCues(blocks []subtitleCue) {
+	for i := range blocks {
+		cue := &blocks[i]
+		if !cue.translated || cue.end <= cue.start {
+			continue
+		}
+		chars := utf8.RuneCountInString(stripSubtitleTags(strings.Join(cue.lines[cue.text:], " ")))
+		needed := cue.start + time.Duration(float64(chars)/subtitleMaxCPS*float64(time.Second)).Round(time.Millisecond)
+		if needed <= cue.end {
+			continue
+		}
+		for j := i + 1; j < len(blocks); j++ {
+			if blocks[j].isCue() && !blocks[j].dropped {
+				if limit := blocks[j].start - subtitleMinGap; needed > limit {
+					needed = limit
+				}
+				break
+			}
+		}
+		if needed > cue.end {
+			cue.setEnd(needed)
+		}
+	}
+}
+
+// stripSubtitleTags removes inline styling and SSA overrides from cue text.
+func stripSubtitleTags(text string) string {
+	text = subtitleTagPattern.ReplaceAllString(text, "")
+	return strings.TrimSpace(inlineTagPattern.ReplaceAllString(text, ""))
+}
+
+// SubtitleText returns the spoken text of an SRT or WebVTT file, one cue
+// per line, without timecodes, numbering or styling.
+func SubtitleText(data []byte) string {
+	text := strings.ReplaceAll(strings.TrimPrefix(string(data), "\uFEFF"), "\r\n", "\n")
+	var lines []string
+	for _, block := range parseSubtitles(strings.TrimRight(text, "\n")) {
+		if block.isCue() {
+			if spoken := stripSubtitleTags(block.content()); spoken != "" {
+				lines = append(lines, spoken)
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
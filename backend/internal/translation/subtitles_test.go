@@ -0,0 +1,114 @@
+package translation
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sampleSRT = "1\r\n00:00:01,000 --> 00:00:03,000\r\n{\\an8}Welcome to\r\n\r\n2\r\n00:00:03,000 --> 00:00:05,000\r\nthe <i>show</i>.\r\n\r\n3\r\n00:00:06,000 --> 00:00:08,000\r\n- Hi.\r\n- Hello.\r\n"
+
+func TestSubtitleSegmentsJoinSentences(t *testing.T) {
+	segments, err := DocumentSegments("ep1.srt", []byte(sampleSRT))
+	if err != nil {
+		t.Fatalf("segments: %v", err)
+	}
+	expected := []string{"Welcome to the <i>show</i>.", "- Hi.", "- Hello."}
+	if !reflect.DeepEqual(segments, expected) {
+		t.Fatalf("expected %q got %q", expected, segments)
+	}
+}
+
+func TestReassembleSubtitlesKeepsTimecodes(t *testing.T) {
+	out, err := ReassembleDocument(context.Background(), "ep1.srt", "DE", []byte(sampleSRT), func(_ context.Context, segments []string) ([]string, error) {
+		return []string{"Willkommen in der <i>Sendung</i>.", "- Hallo.", "- Guten Tag."}, nil
+	})
+	if err != nil {
+		t.Fatalf("reassemble: %v", err)
+	}
+	expected := "1\r\n00:00:01,000 --> 00:00:03,000\r\n{\\an8}Willkommen in\r\n\r\n2\r\n00:00:03,000 --> 00:00:05,000\r\nder <i>Sendung</i>.\r\n\r\n3\r\n00:00:06,000 --> 00:00:08,000\r\n- Hallo.\r\n- Guten Tag.\r\n"
+	if string(out) != expected {
+		t.Fatalf("expected %q got %q", expected, out)
+	}
+}
+
+func TestReassembleWebVTTWrapsLines(t *testing.T) {
+	input := "WEBVTT\n\nNOTE translated by hand --> not a cue\n\nintro\n00:01.000 --> 00:05.000 align:start\n<v Ann>Hi\n"
+	long := "This translation is far too long to fit on a single subtitle line"
+	out, err := ReassembleDocument(context.Background(), "talk.vtt", "EN", []byte(input), func(_ context.Context, segments []string) ([]string, error) {
+		if len(segments) != 1 || segments[0] != "<v Ann>Hi" {
+			t.Fatalf("unexpected segments %q", segments)
+		}
+		return []string{long}, nil
+	})
+	if err != nil {
+		t.Fatalf("reassemble: %v", err)
+	}
+	expected := "WEBVTT\n\nNOTE translated by hand --> not a cue\n\nintro\n00:01.000 --> 00:05.000 align:start\nThis translation is far too long\nto fit on a single subtitle line\n"
+	if string(out) != expected {
+		t.Fatalf("expected %q got %q", expected, out)
+	}
+}
+
+func TestDistributeSubtitleTextByDuration(t *testing.T) {
+	pieces := distributeSubtitleText("one two three four five six", []float64{1, 2})
+	if !reflect.DeepEqual(pieces, []string{"one two", "three four five six"}) {
+		t.Fatalf("unexpected pieces %q", pieces)
+	}
+	pieces = distributeSubtitleText("Ja.", []float64{1, 1})
+	if !reflect.DeepEqual(pieces, []string{"Ja.", ""}) {
+		t.Fatalf("unexpected pieces %q", pieces)
+	}
+	pieces = distributeSubtitleText("你好世界", []float64{1, 1})
+	if strings.Join(pieces, "") != "你好世界" || pieces[0] == "" || pieces[1] == "" {
+		t.Fatalf("unexpected pieces %q", pieces)
+	}
+}
+
+func TestReassembleSubtitlesMergesCuesLeftWithoutText(t *testing.T) {
+	input := "1\n00:00:01,000 --> 00:00:02,000\nYes,\n\n2\n00:00:02,000 --> 00:00:03,000\nof course.\n\n3\n00:00:05,000 --> 00:00:06,000\nBye.\n"
+	out, err := ReassembleDocument(context.Background(), "a.srt", "DE", []byte(input), func(_ context.Context, segments []string) ([]string, error) {
+		return []string{"Ja.", "Tschüss."}, nil
+	})
+	if err != nil {
+		t.Fatalf("reassemble: %v", err)
+	}
+	expected := "1\n00:00:01,000 --> 00:00:03,000\nJa.\n\n2\n00:00:05,000 --> 00:00:06,000\nTschüss.\n"
+	if string(out) != expected {
+		t.Fatalf("expected %q got %q", expected, out)
+	}
+}
+
+func TestReassembleSubtitlesExtendsFastCues(t *testing.T) {
+	input := "1\n00:00:01,000 --> 00:00:02,000\nHi.\n\n2\n00:00:04,000 --> 00:00:05,000\nBye.\n"
+	out, err := ReassembleDocument(context.Background(), "a.srt", "DE", []byte(input), func(_ context.Context, segments []string) ([]string, error) {
+		return []string{"Guten Tag, meine Damen und Herren.", "Tschüss."}, nil
+	})
+	if err != nil {
+		t.Fatalf("reassemble: %v", err)
+	}
+	// 34 characters need 1.7s at subtitleMaxCPS.
+	expected := "1\n00:00:01,000 --> 00:00:02,700\nGuten Tag, meine Damen und Herren.\n\n2\n00:00:04,000 --> 00:00:05,000\nTschüss.\n"
+	if string(out) != expected {
+		t.Fatalf("expected %q got %q", expected, out)
+	}
+}
+
+func TestWrapSubtitleLineBreaksSpacelessScripts(t *testing.T) {
+	lines := wrapSubtitleLine("今日はとても良い天気ですね。散歩に行きましょうか。", subtitleSpacelessLineChars)
+	if len(lines) != 2 || strings.Join(lines, "") != "今日はとても良い天気ですね。散歩に行きましょうか。" {
+		t.Fatalf("unexpected lines %q", lines)
+	}
+	for _, line := range lines {
+		if n := len([]rune(line)); n > subtitleSpacelessLineChars {
+			t.Fatalf("line %q has %d characters", line, n)
+		}
+	}
+}
+
+func TestSubtitleTextCountsSpokenTextOnly(t *testing.T) {
+	if text := SubtitleText([]byte(sampleSRT)); text != "Welcome to\nthe show.\n- Hi. - Hello." {
+		t.Fatalf("unexpected text %q", text)
+	}
+}
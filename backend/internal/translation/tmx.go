@@ -0,0 +1,248 @@
+package translation
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// TMXUnit is one bilingual segment pair of a TMX translation unit.
+type TMXUnit struct {
+	SourceLang string
+	TargetLang string
+	Source     string
+	Target     string
+}
+
+// tmxVariant is a <tuv> of a translation unit.
+type tmxVariant struct {
+	lang string
+	text string
+}
+
+// NormalizeLanguageCode converts a TMX/BCP 47 code to the form stored on
+// translations: regional variants offered as targets are kept ("en_us"
+// becomes "EN-US"), any other code is reduced to its base ("de-DE" becomes
+// "DE").
+func NormalizeLanguageCode(code string) string {
+	code = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(code), "_", "-"))
+	if !strings.Contains(code, "-") {
+		return code
+	}
+	for _, lang := range staticLanguages(true) {
+		if lang.Code == code {
+			return code
+		}
+	}
+	return strings.ToUpper(BaseLanguage(code))
+}
+
+// ReadTMX streams the translation units of a TMX 1.4b document and calls fn
+// with the source variant of every unit paired with each other variant. The
+// source language is the unit's srclang, else the header's; with "*all*"
+// the first variant is the source. Inline codes are dropped from segments.
+func ReadTMX(r io.Reader, fn func(TMXUnit) error) error {
+	decoder := xml.NewDecoder(r)
+	headerLang := ""
+	seenRoot := false
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("invalid tmx: %w", err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "tmx":
+			seenRoot = true
+		case "header":
+			headerLang = xmlAttr(start, "srclang")
+		case "tu":
+			variants, err := readTMXUnit(decoder)
+			if err != nil {
+				return fmt.Errorf("invalid tmx: %w", err)
+			}
+			sourceLang := xmlAttr(start, "srclang")
+			if sourceLang == "" {
+				sourceLang = headerLang
+			}
+			if err := emitTMXPairs(variants, sourceLang, fn); err != nil {
+				return err
+			}
+		}
+	}
+	if !seenRoot {
+		return errors.New("invalid tmx: missing <tmx> root element")
+	}
+	return nil
+}
+
+func emitTMXPairs(variants []tmxVariant, sourceLang string, fn func(TMXUnit) error) error {
+	if len(variants) < 2 {
+		return nil
+	}
+	source := -1
+	if sourceLang == "" || sourceLang == "*all*" {
+		source = 0
+	} else {
+		for i, variant := range variants {
+			if NormalizeLanguageCode(variant.lang) == NormalizeLanguageCode(sourceLang) {
+				source = i
+				break
+			}
+		}
+	}
+	if source < 0 {
+		return nil
+	}
+	for i, variant := range variants {
+		if i == source {
+			continue
+		}
+		err := fn(TMXUnit{
+			SourceLang: NormalizeLanguageCode(variants[source].lang),
+			TargetLang: NormalizeLanguageCode(variant.lang),
+			Source:     variants[source].text,
+			Target:     variant.text,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readTMXUnit reads the variants of a <tu> up to its end element.
+func readTMXUnit(decoder *xml.Decoder) ([]tmxVariant, error) {
+	var variants []tmxVariant
+	var current *tmxVariant
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "tuv":
+				variants = append(variants, tmxVariant{lang: xmlAttr(t, "lang")})
+				current = &variants[len(variants)-1]
+			case "seg":
+				text, err := readTMXSegment(decoder)
+				if err != nil {
+					return nil, err
+				}
+				if current != nil {
+					current.text = text
+				}
+			default:
+				// Notes and properties carry no segment text.
+				if err := decoder.Skip(); err != nil {
+					return nil, err
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Local == "tu" {
+				kept := variants[:0]
+				for _, variant := range variants {
+					if variant.lang != "" && NormalizeSegment(variant.text) != "" {
+						kept = append(kept, variant)
+					}
+				}
+				return kept, nil
+			}
+		}
+	}
+}
+
+// readTMXSegment returns the text of a <seg>. Paired and placeholder codes
+// hold escaped native markup, which is kept as markup so inline tags and
+// placeholders survive; highlighted text is kept.
+func readTMXSegment(decoder *xml.Decoder) (string, error) {
+	var builder strings.Builder
+	depth := 0
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", err
+		}
+		switch t := token.(type) {
+		case xml.CharData:
+			builder.Write(t)
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			if depth == 0 {
+				return NormalizeSegment(builder.String()), nil
+			}
+			depth--
+		}
+	}
+}
+
+func xmlAttr(start xml.StartElement, name string) string {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == name {
+			return strings.TrimSpace(attr.Value)
+		}
+	}
+	return ""
+}
+
+// TMXWriter streams translation units as a TMX 1.4b document.
+type TMXWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+// NewTMXWriter writes the TMX header. sourceLang is the header srclang;
+// empty declares "*all*" for mixed source languages.
+func NewTMXWriter(w io.Writer, sourceLang string) *TMXWriter {
+	if sourceLang == "" {
+		sourceLang = "*all*"
+	}
+	tw := &TMXWriter{w: bufio.NewWriter(w)}
+	tw.write(xml.Header)
+	tw.write(`<tmx version="1.4">` + "\n")
+	tw.write(`  <header creationtool="Kaminskyi Language Intelligence" creationtoolversion="1.0" datatype="plaintext" segtype="paragraph" adminlang="en" o-tmf="kaminskyi" srclang="` + escapeXML(sourceLang) + `"/>` + "\n")
+	tw.write("  <body>\n")
+	return tw
+}
+
+// Write adds a translation unit.
+func (tw *TMXWriter) Write(unit TMXUnit) error {
+	tw.write(`    <tu srclang="` + escapeXML(unit.SourceLang) + `">` + "\n")
+	tw.write(`      <tuv xml:lang="` + escapeXML(unit.SourceLang) + `"><seg>` + escapeXML(unit.Source) + "</seg></tuv>\n")
+	tw.write(`      <tuv xml:lang="` + escapeXML(unit.TargetLang) + `"><seg>` + escapeXML(unit.Target) + "</seg></tuv>\n")
+	tw.write("    </tu>\n")
+	return tw.err
+}
+
+// Close writes the document footer and flushes the output.
+func (tw *TMXWriter) Close() error {
+	tw.write("  </body>\n</tmx>\n")
+	if tw.err != nil {
+		return tw.err
+	}
+	return tw.w.Flush()
+}
+
+func (tw *TMXWriter) write(s string) {
+	if tw.err == nil {
+		_, tw.err = tw.w.WriteString(s)
+	}
+}
+
+func escapeXML(s string) string {
+	var builder strings.Builder
+	_ = xml.EscapeText(&builder, []byte(s))
+	return builder.String()
+}
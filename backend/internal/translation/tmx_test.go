@@ -0,0 +1,71 @@
+package translation
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReadTMX(t *testing.T) {
+	doc := `<?xml version="1.0" encoding="UTF-8"?>
+<tmx version="1.4">
+  <header srclang="en-US" datatype="plaintext" segtype="sentence" adminlang="en" creationtool="x" creationtoolversion="1" o-tmf="x"/>
+  <body>
+    <tu>
+      <prop type="x-client">ACME</prop>
+      <tuv xml:lang="de-DE"><seg>Hallo <bpt i="1">&lt;b&gt;</bpt>Welt<ept i="1">&lt;/b&gt;</ept></seg></tuv>
+      <tuv xml:lang="en-US"><seg>Hello <hi>world</hi></seg></tuv>
+      <tuv xml:lang="pt-br"><seg>Olá mundo</seg></tuv>
+    </tu>
+    <tu><tuv xml:lang="en-US"><seg>Untranslated</seg></tuv></tu>
+  </body>
+</tmx>`
+	var units []TMXUnit
+	err := ReadTMX(strings.NewReader(doc), func(unit TMXUnit) error {
+		units = append(units, unit)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []TMXUnit{
+		{SourceLang: "EN-US", TargetLang: "DE", Source: "Hello world", Target: "Hallo <b>Welt</b>"},
+		{SourceLang: "EN-US", TargetLang: "PT-BR", Source: "Hello world", Target: "Olá mundo"},
+	}
+	if len(units) != len(want) {
+		t.Fatalf("expected %d units, got %+v", len(want), units)
+	}
+	for i := range want {
+		if units[i] != want[i] {
+			t.Errorf("unit %d: expected %+v, got %+v", i, want[i], units[i])
+		}
+	}
+}
+
+func TestTMXRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	writer := NewTMXWriter(&buf, "EN")
+	unit := TMXUnit{SourceLang: "EN", TargetLang: "DE", Source: "Fish & chips <now>", Target: "Fisch & Pommes <jetzt>"}
+	if err := writer.Write(unit); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	var units []TMXUnit
+	if err := ReadTMX(&buf, func(u TMXUnit) error {
+		units = append(units, u)
+		return nil
+	}); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if len(units) != 1 || units[0] != unit {
+		t.Fatalf("expected %+v, got %+v", unit, units)
+	}
+}
+
+func TestReadTMXRejectsOtherXML(t *testing.T) {
+	if err := ReadTMX(strings.NewReader("<xliff/>"), func(TMXUnit) error { return nil }); err == nil {
+		t.Fatal("expected error for non-TMX document")
+	}
+}
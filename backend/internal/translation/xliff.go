@@ -0,0 +1,348 @@
+package translation
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// XLIFFVersion selects the XLIFF dialect of an export.
+type XLIFFVersion string
+
+const (
+	XLIFF12 XLIFFVersion = "1.2"
+	XLIFF20 XLIFFVersion = "2.0"
+)
+
+// XLIFFUnit is one segment of a document with its translation.
+type XLIFFUnit struct {
+	ID     string
+	Source string
+	Target string
+}
+
+// XLIFFDocument is a bilingual document exchanged with CAT tools.
+type XLIFFDocument struct {
+	Version    XLIFFVersion
+	Original   string
+	SourceLang string
+	TargetLang string
+	Units      []XLIFFUnit
+}
+
+// inlineTagPattern matches markup inside segment text, which is exported as
+// a protected placeholder so reviewers cannot break it.
+var inlineTagPattern = regexp.MustCompile(`</?[A-Za-z][A-Za-z0-9:_-]*(?:\s[^<>]*)?/?>`)
+
+// inlinePart is either translatable text or an inline code.
+type inlinePart struct {
+	text string
+	code bool
+	id   int
+}
+
+// WriteXLIFF renders doc as XLIFF 1.2 or 2.0. Markup inside segments is
+// written as <ph> placeholders carrying the original code.
+func WriteXLIFF(w io.Writer, doc XLIFFDocument) error {
+	bw := bufio.NewWriter(w)
+	var err error
+	write := func(s string) {
+		if err == nil {
+			_, err = bw.WriteString(s)
+		}
+	}
+	write(xml.Header)
+	switch doc.Version {
+	case XLIFF12:
+		write(`<xliff version="1.2" xmlns="urn:oasis:names:tc:xliff:document:1.2">` + "\n")
+		write(`  <file original="` + escapeXML(doc.Original) + `" source-language="` + escapeXML(doc.SourceLang) + `" target-language="` + escapeXML(doc.TargetLang) + `" datatype="plaintext">` + "\n")
+		write("    <body>\n")
+		for _, unit := range doc.Units {
+			source, target := inlineParts(unit.Source, unit.Target)
+			write(`      <trans-unit id="` + escapeXML(unit.ID) + `">` + "\n")
+			write("        <source>" + renderInline12(source) + "</source>\n")
+			write(`        <target state="translated">` + renderInline12(target) + "</target>\n")
+			write("      </trans-unit>\n")
+		}
+		write("    </body>\n  </file>\n</xliff>\n")
+	case XLIFF20:
+		write(`<xliff version="2.0" xmlns="urn:oasis:names:tc:xliff:document:2.0" srcLang="` + escapeXML(doc.SourceLang) + `" trgLang="` + escapeXML(doc.TargetLang) + `">` + "\n")
+		write(`  <file id="f1" original="` + escapeXML(doc.Original) + `">` + "\n")
+		for _, unit := range doc.Units {
+			source, target := inlineParts(unit.Source, unit.Target)
+			write(`    <unit id="` + escapeXML(unit.ID) + `">` + "\n")
+			if data := originalData(source, target); data != "" {
+				write("      <originalData>" + data + "</originalData>\n")
+			}
+			write(`      <segment state="translated">` + "\n")
+			write("        <source>" + renderInline20(source) + "</source>\n")
+			write("        <target>" + renderInline20(target) + "</target>\n")
+			write("      </segment>\n    </unit>\n")
+		}
+		write("  </file>\n</xliff>\n")
+	default:
+		return fmt.Errorf("unsupported xliff version %q", doc.Version)
+	}
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// inlineParts splits source and target into text and codes. Source codes
+// are numbered in order; a target code reuses the id of an identical source
+// code so CAT tools can pair them.
+func inlineParts(source, target string) ([]inlinePart, []inlinePart) {
+	sourceParts := splitInline(source)
+	available := make(map[string][]int)
+	next := 1
+	for i := range sourceParts {
+		if sourceParts[i].code {
+			sourceParts[i].id = next
+			available[sourceParts[i].text] = append(available[sourceParts[i].text], next)
+			next++
+		}
+	}
+	targetParts := splitInline(target)
+	for i := range targetParts {
+		if !targetParts[i].code {
+			continue
+		}
+		if ids := available[targetParts[i].text]; len(ids) > 0 {
+			targetParts[i].id = ids[0]
+			available[targetParts[i].text] = ids[1:]
+			continue
+		}
+		targetParts[i].id = next
+		next++
+	}
+	return sourceParts, targetParts
+}
+
+func splitInline(text string) []inlinePart {
+	var parts []inlinePart
+	last := 0
+	for _, loc := range inlineTagPattern.FindAllStringIndex(text, -1) {
+		if loc[0] > last {
+			parts = append(parts, inlinePart{text: text[last:loc[0]]})
+		}
+		parts = append(parts, inlinePart{text: text[loc[0]:loc[1]], code: true})
+		last = loc[1]
+	}
+	if last < len(text) {
+		parts = append(parts, inlinePart{text: text[last:]})
+	}
+	return parts
+}
+
+func renderInline12(parts []inlinePart) string {
+	var builder strings.Builder
+	for _, part := range parts {
+		if part.code {
+			builder.WriteString(`<ph id="` + strconv.Itoa(part.id) + `">` + escapeXML(part.text) + "</ph>")
+			continue
+		}
+		builder.WriteString(escapeXML(part.text))
+	}
+	return builder.String()
+}
+
+func renderInline20(parts []inlinePart) string {
+	var builder strings.Builder
+	for _, part := range parts {
+		if part.code {
+			id := strconv.Itoa(part.id)
+			builder.WriteString(`<ph id="` + id + `" dataRef="d` + id + `"/>`)
+			continue
+		}
+		builder.WriteString(escapeXML(part.text))
+	}
+	return builder.String()
+}
+
+// originalData lists the codes of a 2.0 unit once per id.
+func originalData(source, target []inlinePart) string {
+	var builder strings.Builder
+	seen := make(map[int]bool)
+	for _, part := range append(append([]inlinePart(nil), source...), target...) {
+		if part.code && !seen[part.id] {
+			seen[part.id] = true
+			builder.WriteString(`<data id="d` + strconv.Itoa(part.id) + `">` + escapeXML(part.text) + "</data>")
+		}
+	}
+	return builder.String()
+}
+
+// ReadXLIFF parses an XLIFF 1.2 or 2.0 document. Placeholders are replaced
+// by the codes they protect; the segments of a 2.0 unit are joined.
+func ReadXLIFF(r io.Reader) (*XLIFFDocument, error) {
+	decoder := xml.NewDecoder(r)
+	doc := &XLIFFDocument{}
+	seenRoot := false
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid xliff: %w", err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "xliff":
+			seenRoot = true
+			doc.Version = XLIFFVersion(xmlAttr(start, "version"))
+			doc.SourceLang = xmlAttr(start, "srcLang")
+			doc.TargetLang = xmlAttr(start, "trgLang")
+		case "file":
+			if doc.Original == "" {
+				doc.Original = xmlAttr(start, "original")
+			}
+			if lang := xmlAttr(start, "source-language"); lang != "" {
+				doc.SourceLang = lang
+			}
+			if lang := xmlAttr(start, "target-language"); lang != "" {
+				doc.TargetLang = lang
+			}
+		case "trans-unit", "unit":
+			unit, err := readXLIFFUnit(decoder, start)
+			if err != nil {
+				return nil, fmt.Errorf("invalid xliff: %w", err)
+			}
+			doc.Units = append(doc.Units, unit)
+		}
+	}
+	if !seenRoot {
+		return nil, errors.New("invalid xliff: missing <xliff> root element")
+	}
+	if doc.Version != XLIFF12 && doc.Version != XLIFF20 {
+		return nil, fmt.Errorf("unsupported xliff version %q", doc.Version)
+	}
+	return doc, nil
+}
+
+// readXLIFFUnit reads a 1.2 <trans-unit> or 2.0 <unit> up to its end.
+func readXLIFFUnit(decoder *xml.Decoder, start xml.StartElement) (XLIFFUnit, error) {
+	unit := XLIFFUnit{ID: xmlAttr(start, "id")}
+	data := make(map[string]string)
+	var source, target strings.Builder
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return unit, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "segment", "ignorable", "originalData":
+				// Containers of the elements below.
+			case "data":
+				text, err := readXMLText(decoder)
+				if err != nil {
+					return unit, err
+				}
+				data[xmlAttr(t, "id")] = text
+			case "source", "target":
+				text, err := readXLIFFInline(decoder, data)
+				if err != nil {
+					return unit, err
+				}
+				if t.Name.Local == "source" {
+					source.WriteString(text)
+				} else {
+					target.WriteString(text)
+				}
+			default:
+				// Notes, alternative translations and metadata.
+				if err := decoder.Skip(); err != nil {
+					return unit, err
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Local == start.Name.Local {
+				unit.Source = source.String()
+				unit.Target = target.String()
+				return unit, nil
+			}
+		}
+	}
+}
+
+// readXLIFFInline returns the text of a <source> or <target>, restoring
+// the codes of 1.2 native-code elements and 2.0 dataRef placeholders.
+func readXLIFFInline(decoder *xml.Decoder, data map[string]string) (string, error) {
+	var builder strings.Builder
+	var closing []string
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", err
+		}
+		switch t := token.(type) {
+		case xml.CharData:
+			builder.Write(t)
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "ph", "bpt", "ept", "it", "sc", "ec":
+				if ref := xmlAttr(t, "dataRef"); ref != "" {
+					builder.WriteString(data[ref])
+					if err := decoder.Skip(); err != nil {
+						return "", err
+					}
+					continue
+				}
+				text, err := readXMLText(decoder)
+				if err != nil {
+					return "", err
+				}
+				builder.WriteString(text)
+			case "x", "bx", "ex":
+				if err := decoder.Skip(); err != nil {
+					return "", err
+				}
+			case "pc":
+				builder.WriteString(data[xmlAttr(t, "dataRefStart")])
+				closing = append(closing, data[xmlAttr(t, "dataRefEnd")])
+			default:
+				closing = append(closing, "")
+			}
+		case xml.EndElement:
+			if len(closing) == 0 {
+				return builder.String(), nil
+			}
+			builder.WriteString(closing[len(closing)-1])
+			closing = closing[:len(closing)-1]
+		}
+	}
+}
+
+// readXMLText returns the character data of the current element.
+func readXMLText(decoder *xml.Decoder) (string, error) {
+	var builder strings.Builder
+	depth := 0
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", err
+		}
+		switch t := token.(type) {
+		case xml.CharData:
+			builder.Write(t)
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			if depth == 0 {
+				return builder.String(), nil
+			}
+			depth--
+		}
+	}
+}
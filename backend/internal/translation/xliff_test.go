@@ -0,0 +1,57 @@
+package translation
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestXLIFFRoundTrip(t *testing.T) {
+	for _, version := range []XLIFFVersion{XLIFF12, XLIFF20} {
+		doc := XLIFFDocument{
+			Version:    version,
+			Original:   "report.html",
+			SourceLang: "EN",
+			TargetLang: "DE",
+			Units: []XLIFFUnit{
+				{ID: "1", Source: "Hello <b>world</b> & friends", Target: "Hallo <b>Welt</b> & Freunde"},
+				{ID: "2", Source: "Plain", Target: "Einfach"},
+			},
+		}
+		var buf bytes.Buffer
+		if err := WriteXLIFF(&buf, doc); err != nil {
+			t.Fatalf("%s: write: %v", version, err)
+		}
+		if strings.Contains(buf.String(), "&lt;b&gt;Welt") {
+			t.Fatalf("%s: expected markup to be protected, got %s", version, buf.String())
+		}
+		got, err := ReadXLIFF(&buf)
+		if err != nil {
+			t.Fatalf("%s: read: %v", version, err)
+		}
+		if got.Version != version || got.SourceLang != "EN" || got.TargetLang != "DE" || got.Original != "report.html" {
+			t.Fatalf("%s: unexpected header %+v", version, got)
+		}
+		if len(got.Units) != len(doc.Units) {
+			t.Fatalf("%s: expected %d units, got %d", version, len(doc.Units), len(got.Units))
+		}
+		for i := range doc.Units {
+			if got.Units[i] != doc.Units[i] {
+				t.Errorf("%s: unit %d: expected %+v, got %+v", version, i, doc.Units[i], got.Units[i])
+			}
+		}
+	}
+}
+
+func TestReadXLIFFSkipsAlternatives(t *testing.T) {
+	doc := `<xliff version="1.2"><file source-language="en" target-language="fr" original="a.txt"><body>
+<trans-unit id="1"><source>Yes</source><target>Oui</target><alt-trans><target>Si</target></alt-trans><note>checked</note></trans-unit>
+</body></file></xliff>`
+	got, err := ReadXLIFF(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got.Units) != 1 || got.Units[0].Target != "Oui" {
+		t.Fatalf("unexpected units %+v", got.Units)
+	}
+}
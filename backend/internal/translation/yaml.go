@@ -0,0 +1,242 @@
+package translation
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// Plain scalars YAML reads as booleans, nulls or numbers.
+	yamlLiteralPattern = regexp.MustCompile(`(?i)^(true|false|yes|no|on|off|null|~|[-+]?(\d[\d_]*)?(\.\d+)?([eE][-+]?\d+)?|0x[0-9a-f]+|0o[0-7]+|[-+]?\.inf|\.nan)$`)
+	// Characters that cannot start a plain scalar or change its meaning.
+	yamlIndicators = "-?:,[]{}#&*!|>'\"%@`"
+)
+
+// yamlBlock is a literal or folded block scalar being collected.
+type yamlBlock struct {
+	parentIndent int
+	lines        []string
+}
+
+// rewriteYAML translates the string values of YAML locale files (Rails,
+// Symfony, Flutter and similar), nested mappings and sequences included.
+// Keys, comments, anchors, tags and non-string scalars are kept. Block
+// scalars are translated as one segment with their indentation restored.
+func rewriteYAML(data []byte, replace func(string) string) ([]byte, error) {
+	lines := strings.Split(string(data), "\n")
+	out := make([]string, 0, len(lines))
+	var block *yamlBlock
+	flushBlock := func() {
+		out = append(out, rewriteYAMLBlock(block.lines, replace)...)
+		block = nil
+	}
+	for _, line := range lines {
+		if block != nil {
+			if strings.TrimSpace(line) == "" || yamlIndent(line) > block.parentIndent {
+				block.lines = append(block.lines, line)
+				continue
+			}
+			flushBlock()
+		}
+		prefix, value, ok := splitYAMLValue(line)
+		if !ok {
+			out = append(out, line)
+			continue
+		}
+		if value[0] == '|' || value[0] == '>' {
+			out = append(out, line)
+			block = &yamlBlock{parentIndent: yamlIndent(line)}
+			continue
+		}
+		out = append(out, prefix+rewriteYAMLScalar(value, replace))
+	}
+	if block != nil {
+		flushBlock()
+	}
+	return []byte(strings.Join(out, "\n")), nil
+}
+
+// splitYAMLValue splits a line after its key or sequence indicator. It
+// reports false for lines without an inline value.
+func splitYAMLValue(line string) (string, string, bool) {
+	trimmed := strings.TrimSpace(strings.TrimSuffix(line, "\r"))
+	if trimmed == "" || trimmed[0] == '#' || trimmed[0] == '%' ||
+		strings.HasPrefix(trimmed, "---") || strings.HasPrefix(trimmed, "...") {
+		return "", "", false
+	}
+	offset := yamlIndent(line)
+	item := false
+	for strings.HasPrefix(line[offset:], "- ") {
+		item = true
+		offset += 2
+		for offset < len(line) && line[offset] == ' ' {
+			offset++
+		}
+	}
+	if end := yamlKeyEnd(line[offset:]); end > 0 {
+		offset += end
+		for offset < len(line) && (line[offset] == ' ' || line[offset] == '\t') {
+			offset++
+		}
+	} else if !item {
+		return "", "", false
+	}
+	value := line[offset:]
+	if strings.TrimSpace(value) == "" || value[0] == '#' {
+		return "", "", false
+	}
+	return line[:offset], value, true
+}
+
+// yamlKeyEnd returns the offset after the colon of a mapping key at the
+// start of s, or 0 when s does not start with a key.
+func yamlKeyEnd(s string) int {
+	i := 0
+	switch {
+	case strings.HasPrefix(s, `"`), strings.HasPrefix(s, "'"):
+		end := yamlQuotedEnd(s)
+		if end < 0 {
+			return 0
+		}
+		i = end
+		for i < len(s) && s[i] == ' ' {
+			i++
+		}
+		if i >= len(s) || s[i] != ':' {
+			return 0
+		}
+	case s == "" || strings.ContainsRune("[{&*!|>#", rune(s[0])):
+		return 0
+	default:
+		i = strings.Index(s, ": ")
+		if strings.HasSuffix(s, ":") && (i < 0 || i > len(s)-1) {
+			i = len(s) - 1
+		}
+		if i < 0 || strings.Contains(s[:i], " #") {
+			return 0
+		}
+	}
+	if i+1 < len(s) && s[i+1] != ' ' && s[i+1] != '\t' {
+		return 0
+	}
+	return i + 1
+}
+
+// rewriteYAMLScalar translates an inline scalar and keeps a trailing comment.
+func rewriteYAMLScalar(value string, replace func(string) string) string {
+	switch value[0] {
+	case '"', '\'':
+		end := yamlQuotedEnd(value)
+		if end < 0 {
+			// Multi-line quoted scalars are kept as they are.
+			return value
+		}
+		literal, rest := value[:end], value[end:]
+		var text string
+		if value[0] == '"' {
+			unquoted, err := strconv.Unquote(literal)
+			if err != nil {
+				return value
+			}
+			text = unquoted
+		} else {
+			text = strings.ReplaceAll(literal[1:len(literal)-1], "''", "'")
+		}
+		translated := replaceTrimmed(text, replace)
+		if translated == text {
+			return value
+		}
+		if value[0] == '\'' && !strings.ContainsAny(translated, "\n\t\\") {
+			return "'" + strings.ReplaceAll(translated, "'", "''") + "'" + rest
+		}
+		return strconv.Quote(translated) + rest
+	case '&', '*', '!', '[', '{':
+		return value
+	}
+	text, rest := value, ""
+	if i := strings.Index(value, " #"); i >= 0 {
+		text, rest = value[:i], value[i:]
+	}
+	trailing := text[len(strings.TrimRight(text, " \t\r")):]
+	text = strings.TrimRight(text, " \t\r")
+	if yamlLiteralPattern.MatchString(text) {
+		return value
+	}
+	translated := replace(text)
+	if translated == text {
+		return value
+	}
+	if needsYAMLQuotes(translated) {
+		translated = strconv.Quote(translated)
+	}
+	return translated + trailing + rest
+}
+
+// rewriteYAMLBlock translates the content lines of a block scalar.
+func rewriteYAMLBlock(lines []string, replace func(string) string) []string {
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" && (indent < 0 || yamlIndent(line) < indent) {
+			indent = yamlIndent(line)
+		}
+	}
+	if indent < 0 {
+		return lines
+	}
+	// Trailing blank lines belong to the document, not to the value.
+	end := len(lines)
+	for end > 0 && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	content := make([]string, end)
+	for i, line := range lines[:end] {
+		if len(line) >= indent {
+			content[i] = strings.TrimRight(line[indent:], "\r")
+		}
+	}
+	text := strings.Join(content, "\n")
+	translated := replaceTrimmed(text, replace)
+	if translated == text {
+		return lines
+	}
+	pad := strings.Repeat(" ", indent)
+	var out []string
+	for _, line := range strings.Split(translated, "\n") {
+		if strings.TrimSpace(line) == "" {
+			out = append(out, "")
+			continue
+		}
+		out = append(out, pad+line)
+	}
+	return append(out, lines[end:]...)
+}
+
+// yamlQuotedEnd returns the offset after the quoted scalar at the start of
+// s, or -1 when it does not close on this line.
+func yamlQuotedEnd(s string) int {
+	quote := s[0]
+	for i := 1; i < len(s); i++ {
+		switch {
+		case quote == '"' && s[i] == '\\':
+			i++
+		case s[i] == quote:
+			if quote == '\'' && i+1 < len(s) && s[i+1] == '\'' {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return -1
+}
+
+func needsYAMLQuotes(s string) bool {
+	return s == "" || strings.ContainsRune(yamlIndicators, rune(s[0])) ||
+		strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") ||
+		strings.ContainsAny(s, "\n\t\\") || strings.TrimSpace(s) != s || yamlLiteralPattern.MatchString(s)
+}
+
+func yamlIndent(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
@@ -2,10 +2,13 @@ package worker
 
 import (
 	"bytes"
-
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/hibiken/asynq"
@@ -28,14 +31,15 @@ type Worker struct {
 	files        *repository.FileRepository
 	storage      storage.Provider
 	translateSvc *services.TranslationService
+	memory       *services.MemoryService
 	stripeSvc    *services.PaymentService
-	deepl        *translation.DeepLClient
-	otranslator  *translation.OTranslatorClient
+	providers    *translation.Registry
+	failover     translation.FailoverPolicy
 	logger       zerolog.Logger
 }
 
 // New constructs worker with shared dependencies.
-func New(redisURL string, translations *repository.TranslationRepository, users *repository.UserRepository, files *repository.FileRepository, storage storage.Provider, translateSvc *services.TranslationService, stripeSvc *services.PaymentService, deepl *translation.DeepLClient, otranslator *translation.OTranslatorClient, logger zerolog.Logger) (*Worker, error) {
+func New(redisURL string, translations *repository.TranslationRepository, users *repository.UserRepository, files *repository.FileRepository, storage storage.Provider, translateSvc *services.TranslationService, memory *services.MemoryService, stripeSvc *services.PaymentService, providers *translation.Registry, failover translation.FailoverPolicy, logger zerolog.Logger) (*Worker, error) {
 	opts, err := asynq.ParseRedisURI(redisURL)
 	if err != nil {
 		return nil, err
@@ -47,6 +51,7 @@ func New(redisURL string, translations *repository.TranslationRepository, users
 			"maintenance":  2,
 			"billing":      2,
 		},
+		RetryDelayFunc: retryDelay,
 	})
 	return &Worker{
 		server:       srv,
@@ -55,9 +60,10 @@ func New(redisURL string, translations *repository.TranslationRepository, users
 		files:        files,
 		storage:      storage,
 		translateSvc: translateSvc,
+		memory:       memory,
 		stripeSvc:    stripeSvc,
-		deepl:        deepl,
-		otranslator:  otranslator,
+		providers:    providers,
+		failover:     failover,
 		logger:       logger,
 	}, nil
 }
@@ -110,22 +116,41 @@ func (w *Worker) handleTranslateDocument(ctx context.Context, task *asynq.Task)
 		return err
 	}
 
+	var result []byte
+	var route translation.Route
 	model := translation.GetModelByKey(translationEntity.ModelKey)
 	if model == nil {
-		return fmt.Errorf("model %s not registered", translationEntity.ModelKey)
+		err = fmt.Errorf("%w: %s", translation.ErrUnknownModel, translationEntity.ModelKey)
+	} else {
+		result, route, err = w.performTranslation(ctx, *model, data, translationEntity)
 	}
-
-	result, err := w.performTranslation(ctx, *model, data, translationEntity)
 	if err != nil {
-		_ = w.translations.UpdateStatus(ctx, translationEntity.ID, models.TranslationFailed, &err.Error())
+		reason := err.Error()
+		_ = w.translations.UpdateStatus(ctx, translationEntity.ID, models.TranslationFailed, &reason)
+		if ctx.Err() == nil && translation.IsPermanent(err) {
+			// Re-running a permanent failure would upload and bill again.
+			return fmt.Errorf("%w: %v", asynq.SkipRetry, err)
+		}
 		return err
 	}
 
 	outputName := fmt.Sprintf("translated-%s", translationEntity.OriginalFilename)
-	if err := w.translateSvc.CompleteTranslation(ctx, translationEntity.ID, result, "application/octet-stream", outputName); err != nil {
+	contentType := "application/octet-stream"
+	producesText := w.providers.ProducesText(route, translationEntity.OriginalFilename)
+	if producesText {
+		// Text-level providers return the extracted text for formats they
+		// cannot reassemble.
+		outputName = strings.TrimSuffix(outputName, filepath.Ext(outputName)) + ".txt"
+		contentType = "text/plain; charset=utf-8"
+	}
+	if err := w.translateSvc.CompleteTranslation(ctx, translationEntity.ID, result, contentType, outputName); err != nil {
 		return err
 	}
 
+	if !producesText {
+		w.storeMemory(ctx, translationEntity, data, result)
+	}
+
 	if err := w.generateInvoice(ctx, translationEntity.ID); err != nil {
 		w.logger.Error().Err(err).Msg("failed to generate invoice")
 	}
@@ -133,38 +158,223 @@ func (w *Worker) handleTranslateDocument(ctx context.Context, task *asynq.Task)
 	return nil
 }
 
-func (w *Worker) performTranslation(ctx context.Context, model translation.Model, data []byte, translationEntity *models.Translation) ([]byte, error) {
-	reader := bytes.NewReader(data)
-	switch model.Provider {
-	case translation.ProviderDeepL:
-		opts := translation.DocumentOptions{
-			SourceLang:  translationEntity.SourceLang,
-			TargetLang:  translationEntity.TargetLang,
-			Formality:   fmt.Sprintf("%v", translationEntity.Options["formality"]),
-			TagHandling: fmt.Sprintf("%v", translationEntity.Options["tag_handling"]),
-			FileName:    translationEntity.OriginalFilename,
+// lookupMemory returns the stored translations of the document's segments.
+func (w *Worker) lookupMemory(ctx context.Context, translationEntity *models.Translation, data []byte) map[string]string {
+	if !translation.ReassemblesLosslessly(translationEntity.OriginalFilename) {
+		return nil
+	}
+	segments, err := translation.DocumentSegments(translationEntity.OriginalFilename, data)
+	if err != nil {
+		return nil
+	}
+	stored, err := w.memory.Lookup(ctx, translationEntity.UserID, translationEntity.EffectiveSourceLang(), translationEntity.TargetLang, segments)
+	if err != nil {
+		w.logger.Warn().Err(err).Str("translation_id", translationEntity.ID).Msg("failed to read translation memory")
+		return nil
+	}
+	return stored
+}
+
+// storeMemory adds the segment pairs of a finished translation to the
+// user's translation memory.
+func (w *Worker) storeMemory(ctx context.Context, translationEntity *models.Translation, source, translated []byte) {
+	if !translation.CanReassemble(translationEntity.OriginalFilename) {
+		return
+	}
+	sources, err := translation.DocumentSegments(translationEntity.OriginalFilename, source)
+	if err != nil {
+		return
+	}
+	targets, err := translation.TranslatedSegments(translationEntity.OriginalFilename, translationEntity.TargetLang, translated)
+	if err != nil {
+		w.logger.Warn().Err(err).Str("translation_id", translationEntity.ID).Msg("failed to segment translated document")
+		return
+	}
+	if err := w.memory.Store(ctx, translationEntity, sources, targets); err != nil {
+		w.logger.Error().Err(err).Str("translation_id", translationEntity.ID).Msg("failed to update translation memory")
+	}
+}
+
+func (w *Worker) performTranslation(ctx context.Context, model translation.Model, data []byte, translationEntity *models.Translation) ([]byte, translation.Route, error) {
+	req := translation.DocumentRequest{
+		FileName:       translationEntity.OriginalFilename,
+		SourceLang:     translationEntity.SourceLang,
+		TargetLang:     translationEntity.TargetLang,
+		Formality:      optionString(translationEntity.Options, "formality"),
+		GlossaryID:     optionString(translationEntity.Options, "glossary_id"),
+		TagHandling:    optionString(translationEntity.Options, "tag_handling"),
+		Passes:         int(optionFloat(translationEntity.Options, "passes", 1)),
+		IgnoreComments: optionBool(translationEntity.Options, "ignore_comments"),
+		Job:            remoteJob(translationEntity),
+		OnSubmit: func(job translation.RemoteJob) {
+			// Without the handle a retry uploads and bills the document again,
+			// but failing here would not undo the upload either.
+			if err := w.translations.SetProviderJob(ctx, translationEntity.ID, string(job.Provider), job.Engine, job.ID, job.Key); err != nil {
+				w.logger.Error().Err(err).Str("translation_id", translationEntity.ID).Msg("failed to record provider job")
+			}
+		},
+	}
+	if stored := w.lookupMemory(ctx, translationEntity, data); len(stored) > 0 {
+		req.Memory = stored
+	}
+	if req.Job != nil {
+		w.logger.Info().Str("translation_id", translationEntity.ID).Str("provider", string(req.Job.Provider)).Msg("resuming provider job")
+	}
+	if w.providers.RequiresText(model, translationEntity.OriginalFilename) {
+		text, err := services.ExtractText(translationEntity.OriginalFilename, data)
+		if err != nil {
+			w.logger.Warn().Err(err).Str("translation_id", translationEntity.ID).Msg("failed to extract text for text-only provider")
+		}
+		req.Text = text
+	}
+	onFailover := func(failed translation.Route, err error, next translation.Route) {
+		w.logger.Warn().Err(err).
+			Str("translation_id", translationEntity.ID).
+			Str("failed_provider", string(failed.Provider)).
+			Str("next_provider", string(next.Provider)).
+			Str("next_engine", next.Engine).
+			Msg("provider failover")
+	}
+	var result []byte
+	var route translation.Route
+	var err error
+	if optionBool(translationEntity.Options, "split_oversized") && model.MaxCharacters > 0 && translation.CanReassemble(translationEntity.OriginalFilename) {
+		result, route, err = w.translateParts(ctx, model, data, req, translationEntity, onFailover)
+	} else {
+		result, route, err = w.providers.TranslateDocument(ctx, model, data, req, w.failover, onFailover)
+	}
+	if err != nil {
+		return nil, route, err
+	}
+	if err := w.translations.SetProcessedBy(ctx, translationEntity.ID, string(route.Provider), route.Engine); err != nil {
+		w.logger.Error().Err(err).Str("translation_id", translationEntity.ID).Msg("failed to record processing provider")
+	}
+	return result, route, nil
+}
+
+// translateParts translates a document above the model's character limit
+// as a sequence of parts split at segment boundaries and joins the results
+// into one document. Every translated part and every remote job of a part is
+// kept in storage until the document is joined, so a retry resumes with the
+// first unfinished part instead of billing the finished ones again.
+func (w *Worker) translateParts(ctx context.Context, model translation.Model, data []byte, req translation.DocumentRequest, translationEntity *models.Translation, onFailover translation.FailoverFunc) ([]byte, translation.Route, error) {
+	parts, err := translation.SplitDocument(translationEntity.OriginalFilename, data, model.MaxCharacters)
+	if err != nil {
+		return nil, translation.Route{}, err
+	}
+	if len(parts) == 1 {
+		return w.providers.TranslateDocument(ctx, model, data, req, w.failover, onFailover)
+	}
+	var route translation.Route
+	var keys []string
+	for i := range parts {
+		key := partKey(translationEntity, i, len(parts))
+		keys = append(keys, key+".json", key+".job.json")
+		if stored, ok := w.loadPart(ctx, key, parts[i]); ok {
+			parts[i], route = stored.Part, stored.Route
+			w.logger.Info().Str("translation_id", translationEntity.ID).Int("part", i+1).Int("parts", len(parts)).Msg("document part already translated")
+			continue
+		}
+		partReq := req
+		partReq.FileName = parts[i].FileName
+		partReq.Text = ""
+		partReq.Job = w.loadPartJob(ctx, key)
+		partReq.OnSubmit = func(job translation.RemoteJob) {
+			w.savePartData(ctx, translationEntity, key+".job.json", job)
 		}
-		return w.deepl.TranslateDocument(ctx, reader, opts)
-	case translation.ProviderOTranslator:
-		passes := 1
-		if v, ok := translationEntity.Options["passes"].(float64); ok {
-			passes = int(v)
+		var partRoute translation.Route
+		if parts[i].Sources != nil {
+			parts[i].Targets, partRoute, err = w.providers.TranslateSegments(ctx, model, parts[i].Sources, partReq, w.failover, onFailover)
+		} else {
+			parts[i].Data, partRoute, err = w.providers.TranslateDocument(ctx, model, parts[i].Data, partReq, w.failover, onFailover)
 		}
-		opts := translation.OTranslatorDocumentOptions{
-			SourceLang:     translationEntity.SourceLang,
-			TargetLang:     translationEntity.TargetLang,
-			Model:          model.Engine,
-			Passes:         passes,
-			Formality:      fmt.Sprintf("%v", translationEntity.Options["formality"]),
-			IgnoreComments: translationEntity.Options["ignore_comments"] == true,
-			PreserveFormat: true,
+		if err != nil {
+			return nil, partRoute, fmt.Errorf("part %d of %d: %w", i+1, len(parts), err)
 		}
-		return w.otranslator.TranslateDocument(ctx, reader, translationEntity.OriginalFilename, opts)
-	default:
-		return nil, fmt.Errorf("unsupported provider %s", model.Provider)
+		route = partRoute
+		w.savePartData(ctx, translationEntity, key+".json", storedPart{Part: parts[i], Route: route})
+		w.logger.Info().Str("translation_id", translationEntity.ID).Int("part", i+1).Int("parts", len(parts)).Msg("document part translated")
+	}
+	result, err := translation.JoinDocument(translationEntity.OriginalFilename, translationEntity.TargetLang, data, parts)
+	if err != nil {
+		return nil, route, err
+	}
+	for _, key := range keys {
+		_ = w.storage.Delete(ctx, key)
+	}
+	return result, route, nil
+}
+
+// storedPart is a translated document part kept for retries.
+type storedPart struct {
+	Part  translation.DocumentPart `json:"part"`
+	Route translation.Route        `json:"route"`
+}
+
+// partKey is the storage key prefix of part i of count parts. The count is
+// part of the key so results of a different split are never reused.
+func partKey(t *models.Translation, i, count int) string {
+	return fmt.Sprintf("users/%s/translations/%s/parts/%d-of-%d", t.UserID, t.ID, i+1, count)
+}
+
+// loadPart returns the part translated by an earlier attempt, if it belongs
+// to the same split.
+func (w *Worker) loadPart(ctx context.Context, key string, part translation.DocumentPart) (storedPart, bool) {
+	var stored storedPart
+	if !w.loadPartData(ctx, key+".json", &stored) {
+		return storedPart{}, false
+	}
+	if stored.Part.FileName != part.FileName || stored.Part.Segments != part.Segments {
+		return storedPart{}, false
+	}
+	return stored, true
+}
+
+// loadPartJob returns the remote job an earlier attempt submitted for a part.
+func (w *Worker) loadPartJob(ctx context.Context, key string) *translation.RemoteJob {
+	var job translation.RemoteJob
+	if !w.loadPartData(ctx, key+".job.json", &job) {
+		return nil
+	}
+	return &job
+}
+
+func (w *Worker) loadPartData(ctx context.Context, key string, value interface{}) bool {
+	reader, err := w.storage.Get(ctx, key)
+	if err != nil {
+		return false
+	}
+	defer reader.Close()
+	return json.NewDecoder(reader).Decode(value) == nil
+}
+
+// savePartData stores retry state of a part. Failing to store it only costs
+// a retranslation on retry, so errors are logged.
+func (w *Worker) savePartData(ctx context.Context, translationEntity *models.Translation, key string, value interface{}) {
+	payload, err := json.Marshal(value)
+	if err == nil {
+		err = w.storage.Save(ctx, key, bytes.NewReader(payload), "application/json")
+	}
+	if err != nil {
+		w.logger.Error().Err(err).Str("translation_id", translationEntity.ID).Str("key", key).Msg("failed to store document part")
 	}
 }
 
+// remoteJob returns the provider job recorded by an earlier attempt.
+func remoteJob(t *models.Translation) *translation.RemoteJob {
+	if t.ProviderJobProvider == nil || t.ProviderJobID == nil {
+		return nil
+	}
+	job := &translation.RemoteJob{Provider: translation.ProviderType(*t.ProviderJobProvider), ID: *t.ProviderJobID}
+	if t.ProviderJobEngine != nil {
+		job.Engine = *t.ProviderJobEngine
+	}
+	if t.ProviderJobKey != nil {
+		job.Key = *t.ProviderJobKey
+	}
+	return job
+}
+
 func (w *Worker) generateInvoice(ctx context.Context, translationID string) error {
 	translationEntity, err := w.translations.GetByID(ctx, translationID)
 	if err != nil {
@@ -215,6 +425,15 @@ func (w *Worker) handleSyncSubscription(ctx context.Context, task *asynq.Task) e
 	return w.stripeSvc.ActivatePremium(ctx, payload.UserID, 30*24*time.Hour)
 }
 
+// retryDelay honors a provider's Retry-After before falling back to
+// asynq's exponential backoff.
+func retryDelay(n int, err error, task *asynq.Task) time.Duration {
+	if delay := translation.RetryAfter(err); delay > 0 {
+		return delay
+	}
+	return asynq.DefaultRetryDelayFunc(n, err, task)
+}
+
 // Shutdown stops worker processing.
 func (w *Worker) Shutdown() {
 	w.server.Shutdown()
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE translations ADD COLUMN IF NOT EXISTS processed_provider TEXT;
+ALTER TABLE translations ADD COLUMN IF NOT EXISTS processed_engine TEXT;
+
+-- +goose Down
+ALTER TABLE translations DROP COLUMN IF EXISTS processed_engine;
+ALTER TABLE translations DROP COLUMN IF EXISTS processed_provider;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE translations ADD COLUMN IF NOT EXISTS detected_source_lang TEXT;
+ALTER TABLE translations ADD COLUMN IF NOT EXISTS detection_confidence DOUBLE PRECISION;
+
+-- +goose Down
+ALTER TABLE translations DROP COLUMN IF EXISTS detection_confidence;
+ALTER TABLE translations DROP COLUMN IF EXISTS detected_source_lang;
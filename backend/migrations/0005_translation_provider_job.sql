@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE translations ADD COLUMN IF NOT EXISTS provider_job_provider TEXT;
+ALTER TABLE translations ADD COLUMN IF NOT EXISTS provider_job_engine TEXT;
+ALTER TABLE translations ADD COLUMN IF NOT EXISTS provider_job_id TEXT;
+ALTER TABLE translations ADD COLUMN IF NOT EXISTS provider_job_key TEXT;
+
+-- +goose Down
+ALTER TABLE translations DROP COLUMN IF EXISTS provider_job_key;
+ALTER TABLE translations DROP COLUMN IF EXISTS provider_job_id;
+ALTER TABLE translations DROP COLUMN IF EXISTS provider_job_engine;
+ALTER TABLE translations DROP COLUMN IF EXISTS provider_job_provider;
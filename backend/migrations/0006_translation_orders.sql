@@ -0,0 +1,24 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS translation_orders (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source_lang TEXT,
+    model_key TEXT NOT NULL,
+    original_filename TEXT NOT NULL,
+    price_cents BIGINT NOT NULL,
+    currency TEXT NOT NULL DEFAULT 'EUR',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_translation_orders_user ON translation_orders(user_id);
+
+ALTER TABLE translations ADD COLUMN IF NOT EXISTS order_id UUID REFERENCES translation_orders(id) ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS idx_translations_order ON translations(order_id);
+
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS order_id UUID REFERENCES translation_orders(id) ON DELETE CASCADE;
+
+-- +goose Down
+ALTER TABLE payments DROP COLUMN IF EXISTS order_id;
+DROP INDEX IF EXISTS idx_translations_order;
+ALTER TABLE translations DROP COLUMN IF EXISTS order_id;
+DROP TABLE IF EXISTS translation_orders;
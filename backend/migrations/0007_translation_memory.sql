@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS translation_memory (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source_lang TEXT NOT NULL,
+    target_lang TEXT NOT NULL,
+    source_hash TEXT NOT NULL,
+    source_text TEXT NOT NULL,
+    target_text TEXT NOT NULL,
+    translation_id UUID REFERENCES translations(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, source_lang, target_lang, source_hash)
+);
+
+ALTER TABLE translations ADD COLUMN IF NOT EXISTS tm_matched_characters INT NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE translations DROP COLUMN IF EXISTS tm_matched_characters;
+DROP TABLE IF EXISTS translation_memory;
@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS translation_segment_edits (
+    id UUID PRIMARY KEY,
+    translation_id UUID NOT NULL REFERENCES translations(id) ON DELETE CASCADE,
+    segment_index INT NOT NULL,
+    source_text TEXT NOT NULL,
+    previous_text TEXT NOT NULL,
+    target_text TEXT NOT NULL,
+    edited_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_translation_segment_edits_translation ON translation_segment_edits (translation_id, segment_index);
+
+-- +goose Down
+DROP TABLE IF EXISTS translation_segment_edits;
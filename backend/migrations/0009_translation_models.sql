@@ -0,0 +1,28 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS translation_models (
+    key TEXT PRIMARY KEY,
+    display_name TEXT NOT NULL,
+    tier TEXT NOT NULL DEFAULT '',
+    provider TEXT NOT NULL,
+    engine TEXT NOT NULL,
+    price_per_1860 DOUBLE PRECISION NOT NULL,
+    currency TEXT NOT NULL DEFAULT 'EUR',
+    features JSONB NOT NULL DEFAULT '[]',
+    options JSONB NOT NULL DEFAULT '{}',
+    max_characters INT NOT NULL DEFAULT 0,
+    speed_score INT NOT NULL DEFAULT 0,
+    accuracy_score INT NOT NULL DEFAULT 0,
+    supports_formality BOOLEAN NOT NULL DEFAULT FALSE,
+    supports_glossary BOOLEAN NOT NULL DEFAULT FALSE,
+    supports_documents BOOLEAN NOT NULL DEFAULT FALSE,
+    supports_images BOOLEAN NOT NULL DEFAULT FALSE,
+    supports_xml_tags BOOLEAN NOT NULL DEFAULT FALSE,
+    fallbacks JSONB NOT NULL DEFAULT '[]',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    sort_order INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS translation_models;
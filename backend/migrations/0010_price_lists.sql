@@ -0,0 +1,29 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS price_lists (
+    id UUID PRIMARY KEY,
+    version INT NOT NULL UNIQUE,
+    name TEXT NOT NULL DEFAULT '',
+    valid_from TIMESTAMPTZ NOT NULL,
+    valid_until TIMESTAMPTZ,
+    premium_discount DOUBLE PRECISION NOT NULL DEFAULT 0.20,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS price_list_items (
+    price_list_id UUID NOT NULL REFERENCES price_lists(id) ON DELETE CASCADE,
+    model_key TEXT NOT NULL,
+    price_per_1860 DOUBLE PRECISION NOT NULL,
+    currency TEXT NOT NULL DEFAULT 'EUR',
+    PRIMARY KEY (price_list_id, model_key)
+);
+
+ALTER TABLE translations ADD COLUMN IF NOT EXISTS price_list_version INT;
+ALTER TABLE translations ADD COLUMN IF NOT EXISTS unit_price DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE translations ADD COLUMN IF NOT EXISTS price_discount DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE translations DROP COLUMN IF EXISTS price_discount;
+ALTER TABLE translations DROP COLUMN IF EXISTS unit_price;
+ALTER TABLE translations DROP COLUMN IF EXISTS price_list_version;
+DROP TABLE IF EXISTS price_list_items;
+DROP TABLE IF EXISTS price_lists;
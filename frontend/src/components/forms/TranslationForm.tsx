@@ -117,8 +117,8 @@ export const TranslationForm = () => {
       modelKey: values.modelKey,
       stripTags: values.stripTags,
       options: {
-        formality: values.formality,
-        glossary_id: values.glossary
+        ...(values.formality ? { formality: values.formality } : {}),
+        ...(values.glossary ? { glossary: values.glossary } : {})
       }
     };
 
@@ -242,7 +242,7 @@ export const TranslationForm = () => {
         <label className="text-sm text-white/70">{t('translationForm.file')}</label>
         <input
           type="file"
-          accept=".pdf,.docx,.epub,.txt"
+          accept=".pdf,.docx,.epub,.txt,.md,.html,.htm,.json,.po,.pot,.xml,.strings,.xcstrings,.yaml,.yml,.srt,.vtt"
           required
           onChange={handleFileChange}
           className="mt-1 w-full rounded-xl border border-dashed border-white/30 bg-slate-950/30 px-4 py-6 text-sm text-white/70 hover:border-accent/50 transition-colors"
@@ -28,4 +28,15 @@ export interface ModelDescriptor {
   maxCharacters: number;
   speedScore: number;
   accuracyScore: number;
+  optionSchema?: OptionSpec[];
+}
+
+export interface OptionSpec {
+  key: string;
+  type: 'enum' | 'integer' | 'boolean' | 'string';
+  description: string;
+  values?: string[];
+  min?: number;
+  max?: number;
+  default?: string | number | boolean;
 }